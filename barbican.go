@@ -8,10 +8,31 @@ import (
 	"github.com/artashesbalabekyan/barbican-sdk-go/xhttp"
 )
 
-func NewConnection(ctx context.Context, config *xhttp.Config) (client.Conn, error) {
-	return client.New(ctx, config)
+func NewConnection(ctx context.Context, config *xhttp.Config, opts ...client.Option) (client.Conn, error) {
+	return client.New(ctx, config, opts...)
 }
 
-func NewFakeConnection(ctx context.Context, fakeData map[string][]byte) (client.Conn, error) {
-	return fake.New(ctx, fakeData)
+// NewConnectionFromCloud connects to Barbican using the cloud
+// with the given name from clouds.yaml. If name is empty, the
+// OS_CLOUD environment variable is used.
+func NewConnectionFromCloud(ctx context.Context, name string, opts ...client.Option) (client.Conn, error) {
+	config, err := xhttp.LoadCloudConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(ctx, config, opts...)
+}
+
+// NewConnectionFromEnv connects to Barbican using the
+// OS_* environment variables.
+func NewConnectionFromEnv(ctx context.Context, opts ...client.Option) (client.Conn, error) {
+	config, err := xhttp.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(ctx, config, opts...)
+}
+
+func NewFakeConnection(ctx context.Context, fakeData map[string][]byte, opts ...fake.Option) (client.Conn, error) {
+	return fake.New(ctx, fakeData, opts...)
 }
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// GetSecretACL returns the ACL of the secret addressed by
+// the given secret reference or UUID.
+func (c *Client) GetSecretACL(ctx context.Context, id string) (*ACL, error) {
+	return c.getACL(ctx, c.resourceURL("secrets", id), xerror.ErrKeyNotFound)
+}
+
+// SetSecretACL replaces the ACL of the secret addressed by id.
+func (c *Client) SetSecretACL(ctx context.Context, id string, acl ACL) error {
+	return c.setACL(ctx, c.resourceURL("secrets", id), acl, xerror.ErrKeyNotFound)
+}
+
+// UpdateSecretACL partially updates the ACL of the secret
+// addressed by id. Fields not set in update are left unchanged.
+func (c *Client) UpdateSecretACL(ctx context.Context, id string, update ACLUpdate) error {
+	return c.updateACL(ctx, c.resourceURL("secrets", id), update, xerror.ErrKeyNotFound)
+}
+
+// DeleteSecretACL resets the ACL of the secret addressed by id
+// to the default, i.e. project-wide access without any users.
+func (c *Client) DeleteSecretACL(ctx context.Context, id string) error {
+	return c.deleteACL(ctx, c.resourceURL("secrets", id), xerror.ErrKeyNotFound)
+}
+
+// GetContainerACL returns the ACL of the container addressed
+// by the given container reference or UUID.
+func (c *Client) GetContainerACL(ctx context.Context, ref string) (*ACL, error) {
+	return c.getACL(ctx, c.resourceURL("containers", ref), xerror.ErrContainerNotFound)
+}
+
+// SetContainerACL replaces the ACL of the container addressed by ref.
+func (c *Client) SetContainerACL(ctx context.Context, ref string, acl ACL) error {
+	return c.setACL(ctx, c.resourceURL("containers", ref), acl, xerror.ErrContainerNotFound)
+}
+
+// UpdateContainerACL partially updates the ACL of the container
+// addressed by ref. Fields not set in update are left unchanged.
+func (c *Client) UpdateContainerACL(ctx context.Context, ref string, update ACLUpdate) error {
+	return c.updateACL(ctx, c.resourceURL("containers", ref), update, xerror.ErrContainerNotFound)
+}
+
+// DeleteContainerACL resets the ACL of the container addressed
+// by ref to the default, i.e. project-wide access without any users.
+func (c *Client) DeleteContainerACL(ctx context.Context, ref string) error {
+	return c.deleteACL(ctx, c.resourceURL("containers", ref), xerror.ErrContainerNotFound)
+}
+
+func (c *Client) getACL(ctx context.Context, resourceURL string, notFoundErr xerror.Error) (*ACL, error) {
+	resp, err := c.client.HttpGet(ctx, endpoint(resourceURL, "/acl"), nil)
+	if err != nil {
+		return nil, notFound(err, notFoundErr)
+	}
+
+	var acl ACL
+	if err := json.Unmarshal(resp, &acl); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch ACL of '%s': failed to parse server response: %v", resourceURL, err)
+	}
+	return &acl, nil
+}
+
+func (c *Client) setACL(ctx context.Context, resourceURL string, acl ACL, notFoundErr xerror.Error) error {
+	acl.Read.Created, acl.Read.Updated = "", ""
+
+	request, err := json.Marshal(acl)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPut(ctx, endpoint(resourceURL, "/acl"), request, nil)
+	return notFound(err, notFoundErr)
+}
+
+func (c *Client) updateACL(ctx context.Context, resourceURL string, update ACLUpdate, notFoundErr xerror.Error) error {
+	request, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPatch(ctx, endpoint(resourceURL, "/acl"), request, nil)
+	return notFound(err, notFoundErr)
+}
+
+func (c *Client) deleteACL(ctx context.Context, resourceURL string, notFoundErr xerror.Error) error {
+	_, err := c.client.HttpDelete(ctx, endpoint(resourceURL, "/acl"), nil, nil)
+	return notFound(err, notFoundErr)
+}
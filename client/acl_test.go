@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestACLUpdateMarshalJSON(t *testing.T) {
+	for i, test := range aclUpdateMarshalJSONTests {
+		b, err := json.Marshal(test.Update)
+		if err != nil {
+			t.Fatalf("Test %d: failed to marshal ACL update: %v", i, err)
+		}
+		if string(b) != test.JSON {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, b, test.JSON)
+		}
+	}
+}
+
+func TestACLUpdateApply(t *testing.T) {
+	enabled, disabled := true, false
+	acl := PrivateACL("alice")
+
+	if got := (ACLUpdate{}).Apply(acl); !reflect.DeepEqual(got, acl) {
+		t.Fatalf("Empty update: got %+v - want %+v", got, acl)
+	}
+	got := ACLUpdate{Users: []string{"bob"}, ProjectAccess: &enabled}.Apply(acl)
+	if !reflect.DeepEqual(got.Read.Users, []string{"bob"}) || !got.Read.HasProjectAccess() {
+		t.Fatalf("Got %+v - want users [bob] with project access", got.Read)
+	}
+	if acl.Read.HasProjectAccess() || !acl.Read.HasUser("alice") {
+		t.Fatalf("Apply modified the original ACL: %+v", acl.Read)
+	}
+	if got = (ACLUpdate{Users: []string{}, ProjectAccess: &disabled}).Apply(got); len(got.Read.Users) != 0 || got.Read.HasProjectAccess() {
+		t.Fatalf("Got %+v - want no users and no project access", got.Read)
+	}
+}
+
+func TestACLOperation(t *testing.T) {
+	if !(ACLOperation{}).HasProjectAccess() {
+		t.Fatal("Default ACL does not grant project access")
+	}
+	acl := PrivateACL("alice", "bob")
+	if acl.Read.HasProjectAccess() {
+		t.Fatal("Private ACL grants project access")
+	}
+	if !acl.Read.HasUser("bob") || acl.Read.HasUser("mallory") {
+		t.Fatalf("Got users %v - want [alice bob]", acl.Read.Users)
+	}
+}
+
+func TestSetSecretACL(t *testing.T) {
+	var body []byte
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets/existing/acl", func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		writeJSON(w, http.StatusOK, map[string]string{"acl_ref": server.URL + "/v1/secrets/existing/acl"})
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	acl := PrivateACL("alice")
+	acl.Read.Created, acl.Read.Updated = "2023-01-01T00:00:00", "2023-01-01T00:00:00"
+	if err := client.SetSecretACL(ctx, "existing", acl); err != nil {
+		t.Fatalf("Failed to set ACL: %v", err)
+	}
+	if want := `{"read":{"users":["alice"],"project-access":false}}`; string(body) != want {
+		t.Fatalf("Got request '%s' - want '%s'", body, want)
+	}
+
+	if err := client.SetSecretACL(ctx, "missing", acl); !errors.Is(err, xerror.ErrKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrKeyNotFound)
+	}
+	if _, err := client.GetContainerACL(ctx, "missing"); !errors.Is(err, xerror.ErrContainerNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrContainerNotFound)
+	}
+}
+
+var aclUpdateMarshalJSONTests = []struct {
+	Update ACLUpdate
+	JSON   string
+}{
+	{Update: ACLUpdate{}, JSON: `{"read":{}}`},                                               // 0
+	{Update: ACLUpdate{Users: []string{}}, JSON: `{"read":{"users":[]}}`},                    // 1 - Removes all users
+	{Update: ACLUpdate{Users: []string{"alice"}}, JSON: `{"read":{"users":["alice"]}}`},      // 2
+	{Update: ACLUpdate{ProjectAccess: new(bool)}, JSON: `{"read":{"project-access":false}}`}, // 3
+}
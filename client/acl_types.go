@@ -0,0 +1,71 @@
+package client
+
+import "encoding/json"
+
+// ACL is the access control list of a secret or container.
+// Barbican only supports ACLs for the read operation.
+type ACL struct {
+	Read ACLOperation `json:"read"`
+}
+
+// ACLOperation lists who may perform an operation.
+type ACLOperation struct {
+	Users         []string `json:"users,omitempty"`          // The Keystone user IDs granted access.
+	ProjectAccess *bool    `json:"project-access,omitempty"` // (optional) Whether all project members have access (default: true).
+	Created       string   `json:"created,omitempty"`
+	Updated       string   `json:"updated,omitempty"`
+}
+
+// HasProjectAccess reports whether all members of the owning
+// project may perform the operation. Unless explicitly disabled,
+// Barbican grants project-wide access.
+func (o ACLOperation) HasProjectAccess() bool {
+	return o.ProjectAccess == nil || *o.ProjectAccess
+}
+
+// HasUser reports whether the given user is listed in the ACL.
+func (o ACLOperation) HasUser(user string) bool {
+	for _, u := range o.Users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
+// PrivateACL returns an ACL that disables project-wide access
+// and grants read access only to the given users.
+func PrivateACL(users ...string) ACL {
+	projectAccess := false
+	return ACL{Read: ACLOperation{Users: users, ProjectAccess: &projectAccess}}
+}
+
+// ACLUpdate describes a partial ACL change of the read
+// operation. Nil fields are left unchanged.
+type ACLUpdate struct {
+	Users         []string // nil leaves the users unchanged, an empty slice removes all users.
+	ProjectAccess *bool
+}
+
+func (u ACLUpdate) MarshalJSON() ([]byte, error) {
+	read := map[string]interface{}{}
+	if u.Users != nil {
+		read["users"] = u.Users
+	}
+	if u.ProjectAccess != nil {
+		read["project-access"] = *u.ProjectAccess
+	}
+	return json.Marshal(map[string]interface{}{"read": read})
+}
+
+// Apply returns the ACL resulting from applying u to acl.
+func (u ACLUpdate) Apply(acl ACL) ACL {
+	if u.Users != nil {
+		acl.Read.Users = append([]string{}, u.Users...)
+	}
+	if u.ProjectAccess != nil {
+		projectAccess := *u.ProjectAccess
+		acl.Read.ProjectAccess = &projectAccess
+	}
+	return acl
+}
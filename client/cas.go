@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// ListCAs returns the certificate authorities available to
+// the project. If the project has project CAs, only those are
+// returned. If pluginName is not empty, only the CAs of the
+// certificate plugin with that name are returned.
+func (c *Client) ListCAs(ctx context.Context, pluginName string) ([]CA, error) {
+	params := url.Values{"limit": {fmt.Sprint(DefaultPageSize)}}
+	if pluginName != "" {
+		params.Set("plugin_name", pluginName)
+	}
+	reqURL := c.apiEndpoint("cas")
+
+	var refs []string
+	for {
+		resp, err := c.client.HttpGet(ctx, reqURL, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var response CAsResponse
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return nil, fmt.Errorf("barbican: failed to list CAs: failed to parse server response: %v", err)
+		}
+		refs = append(refs, response.CAs...)
+		if response.Next == "" || len(response.CAs) == 0 {
+			break
+		}
+		reqURL, params = response.Next, nil
+	}
+
+	// Barbican only lists CA references.
+	cas := make([]CA, 0, len(refs))
+	for _, ref := range refs {
+		ca, err := c.GetCA(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, *ca)
+	}
+	return cas, nil
+}
+
+// GetCA returns the certificate authority addressed
+// by the given CA reference or UUID.
+func (c *Client) GetCA(ctx context.Context, ref string) (*CA, error) {
+	return c.getCA(ctx, c.resourceURL("cas", ref))
+}
+
+// GetCACertificate returns the PEM encoded signing certificate
+// of the certificate authority addressed by ref.
+func (c *Client) GetCACertificate(ctx context.Context, ref string) ([]byte, error) {
+	resp, err := c.client.HttpGet(ctx, endpoint(c.resourceURL("cas", ref), "/cacert"), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrCANotFound)
+	}
+	return resp, nil
+}
+
+// GetCAIntermediates returns the PEM encoded intermediate
+// certificates of the certificate authority addressed by ref,
+// i.e. the chain between its signing certificate and the root.
+func (c *Client) GetCAIntermediates(ctx context.Context, ref string) ([]byte, error) {
+	resp, err := c.client.HttpGet(ctx, endpoint(c.resourceURL("cas", ref), "/intermediates"), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrCANotFound)
+	}
+	return resp, nil
+}
+
+// GetPreferredCA returns the certificate authority issuing the
+// certificates of the project's orders without CA: the project's
+// preferred CA, if any, or the global preferred CA. It returns
+// ErrCANotFound if neither is set.
+func (c *Client) GetPreferredCA(ctx context.Context) (*CA, error) {
+	return c.getPreferredCA(ctx, c.apiEndpoint("cas/preferred"))
+}
+
+// SetPreferredCA makes the project CA addressed by ref the
+// preferred CA of the project. The CA must have been added
+// to the project - see AddCAToProject.
+func (c *Client) SetPreferredCA(ctx context.Context, ref string) error {
+	_, err := c.client.HttpPost(ctx, endpoint(c.resourceURL("cas", ref), "/set-preferred"), nil, nil)
+	return notFound(err, xerror.ErrCANotFound)
+}
+
+// AddCAToProject adds the certificate authority addressed by ref
+// to the project CAs. Once a project has project CAs, its orders
+// may only use these. The first project CA becomes the preferred
+// CA of the project.
+//
+// Barbican only allows project administrators to manage
+// project CAs.
+func (c *Client) AddCAToProject(ctx context.Context, ref string) error {
+	_, err := c.client.HttpPost(ctx, endpoint(c.resourceURL("cas", ref), "/add-to-project"), nil, nil)
+	return notFound(err, xerror.ErrCANotFound)
+}
+
+// RemoveCAFromProject removes the certificate authority
+// addressed by ref from the project CAs.
+func (c *Client) RemoveCAFromProject(ctx context.Context, ref string) error {
+	_, err := c.client.HttpPost(ctx, endpoint(c.resourceURL("cas", ref), "/remove-from-project"), nil, nil)
+	return notFound(err, xerror.ErrCANotFound)
+}
+
+// GetGlobalPreferredCA returns the certificate authority used
+// for projects without a preferred CA. It returns ErrCANotFound
+// if no global preferred CA is set.
+func (c *Client) GetGlobalPreferredCA(ctx context.Context) (*CA, error) {
+	return c.getPreferredCA(ctx, c.apiEndpoint("cas/global-preferred"))
+}
+
+// SetGlobalPreferredCA makes the certificate authority addressed
+// by ref the global preferred CA. Barbican only allows service
+// administrators to change the global preferred CA.
+func (c *Client) SetGlobalPreferredCA(ctx context.Context, ref string) error {
+	_, err := c.client.HttpPost(ctx, endpoint(c.resourceURL("cas", ref), "/set-global-preferred"), nil, nil)
+	return notFound(err, xerror.ErrCANotFound)
+}
+
+// UnsetGlobalPreferredCA removes the global preferred CA.
+func (c *Client) UnsetGlobalPreferredCA(ctx context.Context) error {
+	_, err := c.client.HttpPost(ctx, c.apiEndpoint("cas/unset-global-preferred"), nil, nil)
+	return err
+}
+
+// getPreferredCA fetches the reference of a preferred CA
+// and resolves it.
+func (c *Client) getPreferredCA(ctx context.Context, reqURL string) (*CA, error) {
+	resp, err := c.client.HttpGet(ctx, reqURL, nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrCANotFound)
+	}
+
+	var response CARefResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch preferred CA: failed to parse server response: %v", err)
+	}
+	if response.CARef == "" {
+		return nil, xerror.ErrCANotFound
+	}
+	return c.GetCA(ctx, response.CARef)
+}
+
+func (c *Client) getCA(ctx context.Context, reqURL string) (*CA, error) {
+	resp, err := c.client.HttpGet(ctx, reqURL, nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrCANotFound)
+	}
+
+	var ca CA
+	if err := json.Unmarshal(resp, &ca); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch CA: failed to parse server response: %v", err)
+	}
+	return &ca, nil
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestCAs(t *testing.T) {
+	server := newTestServer(t)
+	newCA := func(id, name string) CA {
+		return CA{
+			CAID:       id,
+			Meta:       []map[string]string{{"name": name}, {"description": name + " of the test server"}, {"ca_signing_certificate": "cert-" + id}},
+			PluginName: "snakeoil_ca",
+			Status:     "ACTIVE",
+		}
+	}
+	cas := map[string]CA{"a": newCA("a", "Root CA"), "b": newCA("b", "Issuing CA")}
+	server.HandleFunc("/v1/cas", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			writeJSON(w, http.StatusOK, CAsResponse{
+				CAs:   []string{server.URL + "/v1/cas/a"},
+				Next:  server.URL + "/v1/cas?offset=1&limit=1",
+				Total: 2,
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, CAsResponse{CAs: []string{server.URL + "/v1/cas/b"}, Total: 2})
+	})
+	for id, ca := range cas {
+		ca := ca
+		server.HandleFunc("/v1/cas/"+id, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, ca)
+		})
+		server.HandleFunc("/v1/cas/"+id+"/cacert", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(ca.SigningCertificate()))
+		})
+		server.HandleFunc("/v1/cas/"+id+"/add-to-project", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	server.HandleFunc("/v1/cas/preferred", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, CARefResponse{CARef: server.URL + "/v1/cas/b"})
+	})
+	server.HandleFunc("/v1/cas/global-preferred", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, CARefResponse{}) // No global preferred CA
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	list, err := client.ListCAs(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to list CAs: %v", err)
+	}
+	if want := []CA{cas["a"], cas["b"]}; !reflect.DeepEqual(list, want) {
+		t.Fatalf("Got %+v - want %+v", list, want)
+	}
+	if ca := list[1]; ca.ID() != "b" || ca.Name() != "Issuing CA" || ca.Description() != "Issuing CA of the test server" || ca.Intermediates() != "" {
+		t.Fatalf("Got unexpected CA attributes %+v", ca)
+	}
+
+	cert, err := client.GetCACertificate(ctx, "a")
+	if err != nil {
+		t.Fatalf("Failed to fetch CA certificate: %v", err)
+	}
+	if string(cert) != "cert-a" {
+		t.Fatalf("Got CA certificate '%s' - want 'cert-a'", cert)
+	}
+
+	preferred, err := client.GetPreferredCA(ctx)
+	if err != nil {
+		t.Fatalf("Failed to fetch preferred CA: %v", err)
+	}
+	if preferred.ID() != "b" {
+		t.Fatalf("Got preferred CA '%s' - want 'b'", preferred.ID())
+	}
+	if _, err = client.GetGlobalPreferredCA(ctx); !errors.Is(err, xerror.ErrCANotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrCANotFound)
+	}
+
+	if err = client.AddCAToProject(ctx, server.URL+"/v1/cas/a"); err != nil {
+		t.Fatalf("Failed to add CA to project: %v", err)
+	}
+	for i, err := range []error{
+		func() error { _, err := client.GetCA(ctx, "missing"); return err }(),
+		func() error { _, err := client.GetCACertificate(ctx, "missing"); return err }(),
+		client.AddCAToProject(ctx, "missing"),
+		client.SetPreferredCA(ctx, "missing"),
+	} {
+		if !errors.Is(err, xerror.ErrCANotFound) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, xerror.ErrCANotFound)
+		}
+	}
+}
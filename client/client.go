@@ -2,14 +2,13 @@ package client
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
 	"github.com/artashesbalabekyan/barbican-sdk-go/xhttp"
 )
 
@@ -17,43 +16,90 @@ const (
 	errValidatePrefix = "invalid barbican config: %s"
 )
 
-func New(ctx context.Context, config *xhttp.Config) (Conn, error) {
-	return newConnection(ctx, config)
+// NameResolution controls how a secret name is resolved
+// when Barbican holds multiple secrets with that name.
+type NameResolution int
+
+const (
+	// NameResolutionFirst resolves a name to the first
+	// matching secret.
+	NameResolutionFirst NameResolution = iota
+
+	// NameResolutionUnique fails with xerror.ErrAmbiguousName
+	// if more than one secret matches a name.
+	NameResolutionUnique
+)
+
+// Option customizes the behavior of a connection.
+type Option func(*Client)
+
+// WithNameResolution sets how secrets are looked up
+// by name. Defaults to NameResolutionFirst.
+func WithNameResolution(mode NameResolution) Option {
+	return func(c *Client) { c.nameResolution = mode }
+}
+
+func New(ctx context.Context, config *xhttp.Config, opts ...Option) (Conn, error) {
+	return newConnection(ctx, config, opts...)
 }
 
-func newConnection(ctx context.Context, config *xhttp.Config) (Conn, error) {
+func newConnection(ctx context.Context, config *xhttp.Config, opts ...Option) (Conn, error) {
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
 
-	var tlsConfig *tls.Config
-	client := &xhttp.Client{
-		Client: http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-				Proxy:           http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   10 * time.Second,
-					KeepAlive: 10 * time.Second,
-					DualStack: true,
-				}).DialContext,
-				ForceAttemptHTTP2:     true,
-				MaxIdleConns:          100,
-				IdleConnTimeout:       30 * time.Second,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-			},
-		},
+	client, err := xhttp.NewClient(*config)
+	if err != nil {
+		return nil, fmt.Errorf(errValidatePrefix, err)
 	}
 
 	// Authenticate and get token
 	if err := client.Authenticate(ctx, *config); err != nil {
 		return nil, err
 	}
-	return &Client{
-		config: config,
+
+	c, err := setupConnection(ctx, client, config, opts...)
+	if err != nil {
+		// Don't leave the token behind. The setup error
+		// is more relevant than a failed revocation.
+		client.Close(ctx)
+		return nil, err
+	}
+	return c, nil
+}
+
+// setupConnection discovers the Barbican endpoint, if not
+// configured, and the API version using the authenticated
+// client.
+func setupConnection(ctx context.Context, client *xhttp.Client, config *xhttp.Config, opts ...Option) (*Client, error) {
+	// Use a copy such that the discovered endpoint
+	// does not leak into the caller's config.
+	cfg := *config
+	if cfg.Endpoint == "" {
+		endpoint, err := client.DiscoverEndpoint(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("barbican: failed to discover endpoint: %v", err)
+		}
+		cfg.Endpoint = endpoint
+	}
+	c := &Client{
+		config: &cfg,
 		client: client,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.discoverVersion(ctx); err != nil {
+		return nil, fmt.Errorf("barbican: failed to discover API version: %v", err)
+	}
+	return c, nil
+}
+
+// Close revokes the connection's authentication token and
+// releases idle HTTP connections. Any subsequent request
+// fails with xerror.ErrClosed.
+func (c *Client) Close(ctx context.Context) error {
+	return c.client.Close(ctx)
 }
 
 // The path elements will not be URL-escaped.
@@ -63,30 +109,72 @@ func endpoint(endpoint string, elems ...string) string {
 	return url
 }
 
+// resourceURL returns the URL of the resource in the given
+// collection, e.g. "secrets", addressed by ref. The ref may
+// either be a full Barbican reference URL or a bare UUID.
+func (c *Client) resourceURL(collection, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref
+	}
+	return c.apiEndpoint(collection, ref)
+}
+
+// apiEndpoint returns the URL of the given path
+// within the negotiated major API version.
+func (c *Client) apiEndpoint(elems ...string) string {
+	return endpoint(c.baseURL, elems...)
+}
+
+// RefID returns the UUID of a Barbican reference URL,
+// i.e. its last path element. A bare UUID is returned
+// as is.
+func RefID(ref string) string {
+	ref = strings.TrimRight(strings.TrimSpace(ref), "/")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// notFound replaces err with the given not-found error
+// if err is an HTTP 404 error. Otherwise, it returns err.
+//
+// The request context of err - e.g. the request ID - is
+// preserved.
+func notFound(err error, notFoundErr xerror.Error) error {
+	var e xerror.Error
+	if errors.As(err, &e) && e.Status() == http.StatusNotFound {
+		return notFoundErr.WithRequest(e.Method(), e.URL(), e.RequestID())
+	}
+	return err
+}
+
 func validateConfig(config *xhttp.Config) error {
 	if config == nil {
 		return fmt.Errorf(errValidatePrefix, "config is nil")
 	}
-	if config.Endpoint == "" {
-		return fmt.Errorf(errValidatePrefix, "endpoint is empty")
+	if config.Endpoint == "" && config.Login.Unscoped {
+		return fmt.Errorf(errValidatePrefix, "endpoint is empty and cannot be discovered with an unscoped token")
+	}
+	if config.Microversion != "" {
+		if _, err := ParseMicroversion(config.Microversion); err != nil {
+			return fmt.Errorf(errValidatePrefix, err)
+		}
 	}
 	if config.Login.AuthUrl == "" {
 		return fmt.Errorf(errValidatePrefix, "auth url is empty")
 	}
-	if config.Login.ProjectName == "" {
-		return fmt.Errorf(errValidatePrefix, "project name is empty")
-	}
-	if config.Login.ProjectDomain == "" {
-		return fmt.Errorf(errValidatePrefix, "project domain is empty")
-	}
-	if config.Login.Username == "" {
-		return fmt.Errorf(errValidatePrefix, "username is empty")
+	identity, err := config.Login.Authenticator().Identity()
+	if err != nil {
+		return fmt.Errorf(errValidatePrefix, err)
 	}
-	if config.Login.UserDomainName == "" {
-		return fmt.Errorf(errValidatePrefix, "user domain name is empty")
+	if identity.ApplicationCredential != nil {
+		// Application credentials are bound to a project.
+		return nil
 	}
-	if config.Login.Password == "" {
-		return fmt.Errorf(errValidatePrefix, "password is empty")
+	if _, err := config.Login.Scope(); err != nil {
+		return fmt.Errorf(errValidatePrefix, err)
 	}
 	return nil
 }
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xhttp"
+)
+
+// testVersions is the version document of a Barbican
+// server supporting the microversions 1.0 up to 1.1.
+const testVersions = `{"versions":{"values":[{"id":"v1","status":"CURRENT","min_version":"1.0","max_version":"1.1"}]}}`
+
+// testServer is a minimal Keystone and Barbican server.
+// Tests register the Barbican API handlers they need
+// via HandleFunc. Unhandled paths respond with 404.
+type testServer struct {
+	*httptest.Server
+	*http.ServeMux
+
+	lock     sync.Mutex
+	root     http.HandlerFunc // Serves the version document
+	catalog  string           // The service catalog of issued tokens
+	auths    []xhttp.AuthRequest
+	revoked  int
+	requests []string // Method and path of all Barbican requests
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	s := &testServer{ServeMux: http.NewServeMux()}
+	s.root = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultipleChoices)
+		w.Write([]byte(testVersions))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.catalog = fmt.Sprintf(`[{"type":"key-manager","name":"barbican","endpoints":[{"interface":"public","region":"RegionOne","url":%q}]}]`, s.URL)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v3/auth/tokens":
+		s.serveTokens(w, r)
+	case "/":
+		s.lock.Lock()
+		root := s.root
+		s.lock.Unlock()
+		root(w, r)
+	default:
+		s.lock.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.lock.Unlock()
+		s.ServeMux.ServeHTTP(w, r)
+	}
+}
+
+func (s *testServer) serveTokens(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		var auth xhttp.AuthRequest
+		if err := json.NewDecoder(r.Body).Decode(&auth); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.auths = append(s.auths, auth)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Subject-Token", "test-token")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":{"expires_at":%q,"catalog":%s}}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339), s.catalog)
+	case http.MethodDelete:
+		s.revoked++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// config returns a Config for connecting to s.
+func (s *testServer) config() *xhttp.Config {
+	return &xhttp.Config{
+		Endpoint: s.URL,
+		Login: xhttp.Credentials{
+			AuthUrl:        s.URL + "/v3",
+			Username:       "user",
+			Password:       "password",
+			UserDomainName: "Default",
+			ProjectName:    "project",
+			ProjectDomain:  "Default",
+		},
+	}
+}
+
+// connect returns a connection to s using the given config
+// or, if nil, the default config of s.
+func (s *testServer) connect(t *testing.T, config *xhttp.Config, opts ...Option) *Client {
+	t.Helper()
+
+	if config == nil {
+		config = s.config()
+	}
+	conn, err := New(context.Background(), config, opts...)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close(context.Background()) })
+	return conn.(*Client)
+}
+
+// Requests returns the method and path of all
+// Barbican requests received so far.
+func (s *testServer) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// writeJSON sends v as JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeFault sends a Barbican fault response.
+func writeFault(w http.ResponseWriter, status int, title, description string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":        status,
+		"title":       title,
+		"description": description,
+	})
+}
+
+func TestNewRevokesTokenOnFailure(t *testing.T) {
+	for i, test := range newRevokesTokenOnFailureTests {
+		server := newTestServer(t)
+		config := server.config()
+		if test.Catalog != "" {
+			server.catalog = test.Catalog
+			config.Endpoint = ""
+		}
+		if test.Versions != "" {
+			server.root = func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMultipleChoices)
+				w.Write([]byte(test.Versions))
+			}
+		}
+		config.Microversion = test.Microversion
+
+		if _, err := New(context.Background(), config); err == nil {
+			t.Fatalf("Test %d: should fail but connected", i)
+		}
+		server.lock.Lock()
+		auths, revoked := len(server.auths), server.revoked
+		server.lock.Unlock()
+		if auths != 1 || revoked != 1 {
+			t.Fatalf("Test %d: got %d issued and %d revoked tokens - want 1 and 1", i, auths, revoked)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	server := newTestServer(t)
+	conn, err := New(context.Background(), server.config())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if err = conn.Close(context.Background()); err != nil {
+		t.Fatalf("Failed to close connection: %v", err)
+	}
+	server.lock.Lock()
+	revoked := server.revoked
+	server.lock.Unlock()
+	if revoked != 1 {
+		t.Fatalf("Got %d revoked tokens - want 1", revoked)
+	}
+	if _, err = conn.GetSecretByID(context.Background(), "existing"); !errors.Is(err, xerror.ErrClosed) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrClosed)
+	}
+}
+
+func TestRefID(t *testing.T) {
+	for i, test := range refIDTests {
+		if id := RefID(test.Ref); id != test.ID {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, id, test.ID)
+		}
+	}
+}
+
+var newRevokesTokenOnFailureTests = []struct {
+	Catalog      string // Replaces the service catalog and clears the endpoint
+	Versions     string // Replaces the version document
+	Microversion string
+}{
+	{Catalog: `[]`},                          // 0 - No key-manager endpoint
+	{Versions: `{"versions":`},               // 1 - Invalid version document
+	{Versions: `{"versions":{"values":[]}}`}, // 2 - No v1
+	{ // 3 - Pinned microversion not supported by the server
+		Versions:     `{"versions":{"values":[{"id":"v1","status":"CURRENT","min_version":"1.1","max_version":"1.1"}]}}`,
+		Microversion: "1.0",
+	},
+}
+
+var refIDTests = []struct {
+	Ref string
+	ID  string
+}{
+	{Ref: "http://localhost:9311/v1/secrets/8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11", ID: "8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11"},
+	{Ref: "http://localhost:9311/v1/secrets/8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11/", ID: "8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11"},
+	{Ref: " 8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11 ", ID: "8b6e5a8c-1d35-4a0e-9b7e-3e1a3c2d9f11"},
+	{Ref: "", ID: ""},
+}
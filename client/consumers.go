@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// RegisterContainerConsumer registers the given consumer of the
+// container addressed by ref. Registering the same consumer twice
+// is not an error.
+func (c *Client) RegisterContainerConsumer(ctx context.Context, ref string, consumer ContainerConsumer) error {
+	request, err := json.Marshal(ContainerConsumer{Name: consumer.Name, URL: consumer.URL})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPost(ctx, c.consumersURL("containers", ref), request, nil)
+	return notFound(err, xerror.ErrContainerNotFound)
+}
+
+// ListContainerConsumers returns all consumers registered
+// for the container addressed by ref.
+func (c *Client) ListContainerConsumers(ctx context.Context, ref string) ([]ContainerConsumer, error) {
+	consumers := []ContainerConsumer{}
+	err := c.listConsumers(ctx, c.consumersURL("containers", ref), func(resp []byte) (string, error) {
+		var page ContainerConsumersResponse
+		if err := json.Unmarshal(resp, &page); err != nil {
+			return "", err
+		}
+		consumers = append(consumers, page.Consumers...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return nil, notFound(err, xerror.ErrContainerNotFound)
+	}
+	return consumers, nil
+}
+
+// RemoveContainerConsumer removes the given consumer from
+// the container addressed by ref. It returns ErrConsumerNotFound
+// if the consumer is not registered and ErrContainerNotFound if
+// the container does not exist.
+func (c *Client) RemoveContainerConsumer(ctx context.Context, ref string, consumer ContainerConsumer) error {
+	request, err := json.Marshal(ContainerConsumer{Name: consumer.Name, URL: consumer.URL})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpDelete(ctx, c.consumersURL("containers", ref), request, nil)
+	return c.containerConsumerNotFound(ctx, ref, err)
+}
+
+// RegisterSecretConsumer registers the given consumer of the
+// secret addressed by id. Secret consumers require Barbican
+// API microversion 1.1 - see Features.SecretConsumers.
+func (c *Client) RegisterSecretConsumer(ctx context.Context, id string, consumer SecretConsumer) error {
+	if !c.version.Features.SecretConsumers {
+		return xerror.ErrNotSupported
+	}
+	request, err := json.Marshal(SecretConsumer{Service: consumer.Service, ResourceType: consumer.ResourceType, ResourceID: consumer.ResourceID})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPost(ctx, c.consumersURL("secrets", id), request, nil)
+	return notFound(err, xerror.ErrKeyNotFound)
+}
+
+// ListSecretConsumers returns all consumers registered for
+// the secret addressed by id. Secret consumers require
+// Barbican API microversion 1.1 - see Features.SecretConsumers.
+func (c *Client) ListSecretConsumers(ctx context.Context, id string) ([]SecretConsumer, error) {
+	if !c.version.Features.SecretConsumers {
+		return nil, xerror.ErrNotSupported
+	}
+	consumers := []SecretConsumer{}
+	err := c.listConsumers(ctx, c.consumersURL("secrets", id), func(resp []byte) (string, error) {
+		var page SecretConsumersResponse
+		if err := json.Unmarshal(resp, &page); err != nil {
+			return "", err
+		}
+		consumers = append(consumers, page.Consumers...)
+		return page.Next, nil
+	})
+	if err != nil {
+		return nil, notFound(err, xerror.ErrKeyNotFound)
+	}
+	return consumers, nil
+}
+
+// RemoveSecretConsumer removes the given consumer from the
+// secret addressed by id. It returns ErrConsumerNotFound if
+// the consumer is not registered and ErrKeyNotFound if the
+// secret does not exist. Secret consumers require Barbican
+// API microversion 1.1 - see Features.SecretConsumers.
+func (c *Client) RemoveSecretConsumer(ctx context.Context, id string, consumer SecretConsumer) error {
+	if !c.version.Features.SecretConsumers {
+		return xerror.ErrNotSupported
+	}
+	request, err := json.Marshal(SecretConsumer{Service: consumer.Service, ResourceType: consumer.ResourceType, ResourceID: consumer.ResourceID})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpDelete(ctx, c.consumersURL("secrets", id), request, nil)
+	return c.secretConsumerNotFound(ctx, id, err)
+}
+
+// containerConsumerNotFound replaces an HTTP 404 error with
+// ErrContainerNotFound if the container addressed by ref does
+// not exist, and with ErrConsumerNotFound otherwise. Barbican
+// responds with 404 in both cases.
+func (c *Client) containerConsumerNotFound(ctx context.Context, ref string, err error) error {
+	var e xerror.Error
+	if !errors.As(err, &e) || e.Status() != http.StatusNotFound {
+		return err
+	}
+	if _, cErr := c.GetContainer(ctx, ref); errors.Is(cErr, xerror.ErrContainerNotFound) {
+		return cErr
+	}
+	return notFound(err, xerror.ErrConsumerNotFound)
+}
+
+// secretConsumerNotFound replaces an HTTP 404 error with
+// ErrKeyNotFound if the secret addressed by id does not
+// exist, and with ErrConsumerNotFound otherwise.
+func (c *Client) secretConsumerNotFound(ctx context.Context, id string, err error) error {
+	var e xerror.Error
+	if !errors.As(err, &e) || e.Status() != http.StatusNotFound {
+		return err
+	}
+	if _, sErr := c.GetSecretByID(ctx, id); errors.Is(sErr, xerror.ErrKeyNotFound) {
+		return sErr
+	}
+	return notFound(err, xerror.ErrConsumerNotFound)
+}
+
+func (c *Client) consumersURL(collection, ref string) string {
+	return endpoint(c.resourceURL(collection, ref), "/consumers")
+}
+
+// listConsumers fetches all pages of a consumer listing and
+// passes each page to parse, which returns the next page link.
+func (c *Client) listConsumers(ctx context.Context, reqURL string, parse func([]byte) (string, error)) error {
+	for reqURL != "" {
+		resp, err := c.client.HttpGet(ctx, reqURL, nil)
+		if err != nil {
+			return err
+		}
+		next, err := parse(resp)
+		if err != nil {
+			return fmt.Errorf("barbican: failed to list consumers: failed to parse server response: %v", err)
+		}
+		reqURL = next
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestRemoveConsumerNotFound(t *testing.T) {
+	server := newTestServer(t)
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "Not Found. Sorry but your consumer is in another castle.")
+	}
+	server.HandleFunc("/v1/containers/existing", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanContainer{ContainerRef: server.URL + "/v1/containers/existing", Type: ContainerTypeGeneric})
+	})
+	server.HandleFunc("/v1/secrets/existing", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanSecret{SecretRef: server.URL + "/v1/secrets/existing"})
+	})
+	server.HandleFunc("/v1/containers/existing/consumers", notFound)
+	server.HandleFunc("/v1/secrets/existing/consumers", notFound)
+	server.HandleFunc("/v1/containers/missing/", notFound)
+	server.HandleFunc("/v1/secrets/missing/", notFound)
+	server.HandleFunc("/v1/secrets/failing/consumers", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusForbidden, "Forbidden", "SecretConsumer deletion attempt not allowed.")
+	})
+	client := server.connect(t, nil)
+
+	ctx := context.Background()
+	containerConsumer := ContainerConsumer{Name: "octavia", URL: "https://octavia/lb/1"}
+	secretConsumer := SecretConsumer{Service: "image", ResourceType: "image", ResourceID: "1"}
+	for i, test := range []struct {
+		Err  error
+		Want error
+	}{
+		{Err: client.RemoveContainerConsumer(ctx, "existing", containerConsumer), Want: xerror.ErrConsumerNotFound}, // 0
+		{Err: client.RemoveContainerConsumer(ctx, "missing", containerConsumer), Want: xerror.ErrContainerNotFound}, // 1
+		{Err: client.RemoveSecretConsumer(ctx, "existing", secretConsumer), Want: xerror.ErrConsumerNotFound},       // 2
+		{Err: client.RemoveSecretConsumer(ctx, "missing", secretConsumer), Want: xerror.ErrKeyNotFound},             // 3
+		{Err: client.RemoveSecretConsumer(ctx, "failing", secretConsumer), Want: xerror.Forbidden},                  // 4
+	} {
+		if !errors.Is(test.Err, test.Want) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, test.Err, test.Want)
+		}
+	}
+}
+
+func TestSecretConsumersNotSupported(t *testing.T) {
+	server := newTestServer(t)
+	config := server.config()
+	config.Microversion = "1.0"
+	client := server.connect(t, config)
+
+	ctx := context.Background()
+	if err := client.RegisterSecretConsumer(ctx, "existing", SecretConsumer{}); !errors.Is(err, xerror.ErrNotSupported) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrNotSupported)
+	}
+	if err := client.RemoveSecretConsumer(ctx, "existing", SecretConsumer{}); !errors.Is(err, xerror.ErrNotSupported) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrNotSupported)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Fatalf("Got requests %v - want none", requests)
+	}
+}
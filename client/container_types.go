@@ -0,0 +1,97 @@
+package client
+
+import "fmt"
+
+func (g GenericContainer) CreateRequest() ContainerCreateRequest {
+	return ContainerCreateRequest{
+		Name:       g.Name,
+		Type:       ContainerTypeGeneric,
+		SecretRefs: g.SecretRefs,
+	}
+}
+
+func (r RSAContainer) CreateRequest() ContainerCreateRequest {
+	return ContainerCreateRequest{
+		Name: r.Name,
+		Type: ContainerTypeRSA,
+		SecretRefs: secretRefs(
+			ContainerSecretPublicKey, r.PublicKeyRef,
+			ContainerSecretPrivateKey, r.PrivateKeyRef,
+			ContainerSecretPrivateKeyPassphrase, r.PrivateKeyPassphraseRef,
+		),
+	}
+}
+
+func (r CertificateContainer) CreateRequest() ContainerCreateRequest {
+	return ContainerCreateRequest{
+		Name: r.Name,
+		Type: ContainerTypeCertificate,
+		SecretRefs: secretRefs(
+			ContainerSecretCertificate, r.CertificateRef,
+			ContainerSecretPrivateKey, r.PrivateKeyRef,
+			ContainerSecretPrivateKeyPassphrase, r.PrivateKeyPassphraseRef,
+			ContainerSecretIntermediates, r.IntermediatesRef,
+		),
+	}
+}
+
+// SecretRef returns the reference of the secret with
+// the given name within the container, if any.
+func (c *BarbicanContainer) SecretRef(name string) (string, bool) {
+	for _, ref := range c.SecretRefs {
+		if ref.Name == name {
+			return ref.SecretRef, true
+		}
+	}
+	return "", false
+}
+
+// Generic returns the container as GenericContainer.
+// Any container can be viewed as a generic one.
+func (c *BarbicanContainer) Generic() GenericContainer {
+	return GenericContainer{
+		Name:       c.Name,
+		SecretRefs: c.SecretRefs,
+	}
+}
+
+// RSA returns the container as RSAContainer. It returns
+// an error if the container is not an rsa container.
+func (c *BarbicanContainer) RSA() (*RSAContainer, error) {
+	if c.Type != ContainerTypeRSA {
+		return nil, fmt.Errorf("barbican: container '%s' is of type '%s', not '%s'", c.ContainerRef, c.Type, ContainerTypeRSA)
+	}
+	rsa := &RSAContainer{Name: c.Name}
+	rsa.PublicKeyRef, _ = c.SecretRef(ContainerSecretPublicKey)
+	rsa.PrivateKeyRef, _ = c.SecretRef(ContainerSecretPrivateKey)
+	rsa.PrivateKeyPassphraseRef, _ = c.SecretRef(ContainerSecretPrivateKeyPassphrase)
+	return rsa, nil
+}
+
+// Certificate returns the container as CertificateContainer.
+// It returns an error if the container is not a certificate
+// container.
+func (c *BarbicanContainer) Certificate() (*CertificateContainer, error) {
+	if c.Type != ContainerTypeCertificate {
+		return nil, fmt.Errorf("barbican: container '%s' is of type '%s', not '%s'", c.ContainerRef, c.Type, ContainerTypeCertificate)
+	}
+	cert := &CertificateContainer{Name: c.Name}
+	cert.CertificateRef, _ = c.SecretRef(ContainerSecretCertificate)
+	cert.PrivateKeyRef, _ = c.SecretRef(ContainerSecretPrivateKey)
+	cert.PrivateKeyPassphraseRef, _ = c.SecretRef(ContainerSecretPrivateKeyPassphrase)
+	cert.IntermediatesRef, _ = c.SecretRef(ContainerSecretIntermediates)
+	return cert, nil
+}
+
+// secretRefs turns name/ref pairs into container secret
+// references, skipping pairs with an empty reference.
+func secretRefs(pairs ...string) []ContainerSecretRef {
+	var refs []ContainerSecretRef
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		refs = append(refs, ContainerSecretRef{Name: pairs[i], SecretRef: pairs[i+1]})
+	}
+	return refs
+}
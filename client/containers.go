@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// CreateContainer stores a new container described by the given
+// spec in Barbican and returns it with its container reference set.
+func (c *Client) CreateContainer(ctx context.Context, spec ContainerSpec) (*BarbicanContainer, error) {
+	createRequest := spec.CreateRequest()
+	request, err := json.Marshal(createRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.HttpPost(ctx, c.apiEndpoint("containers"), request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ContainerRefResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to create container '%s': failed to parse server response: %v", createRequest.Name, err)
+	}
+	return &BarbicanContainer{
+		ContainerRef: response.ContainerRef,
+		Name:         createRequest.Name,
+		SecretRefs:   createRequest.SecretRefs,
+		Type:         createRequest.Type,
+	}, nil
+}
+
+// GetContainer returns the container addressed by the given
+// container reference or UUID.
+func (c *Client) GetContainer(ctx context.Context, ref string) (*BarbicanContainer, error) {
+	resp, err := c.client.HttpGet(ctx, c.resourceURL("containers", ref), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrContainerNotFound)
+	}
+
+	var container BarbicanContainer
+	if err := json.Unmarshal(resp, &container); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch container '%s': failed to parse container: %v", ref, err)
+	}
+	return &container, nil
+}
+
+// DeleteContainer deletes the container addressed by the given
+// container reference or UUID. The secrets referenced by the
+// container are not deleted.
+func (c *Client) DeleteContainer(ctx context.Context, ref string) error {
+	_, err := c.client.HttpDelete(ctx, c.resourceURL("containers", ref), nil, nil)
+	return notFound(err, xerror.ErrContainerNotFound)
+}
+
+// AddContainerSecret adds the given secret reference to the
+// container addressed by ref. Only generic containers can be
+// modified after creation.
+func (c *Client) AddContainerSecret(ctx context.Context, ref string, secret ContainerSecretRef) error {
+	request, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPost(ctx, endpoint(c.resourceURL("containers", ref), "/secrets"), request, nil)
+	return c.containerSecretNotFound(ctx, ref, err)
+}
+
+// RemoveContainerSecret removes the given secret reference from
+// the container addressed by ref. The secret itself is not deleted.
+func (c *Client) RemoveContainerSecret(ctx context.Context, ref string, secret ContainerSecretRef) error {
+	request, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpDelete(ctx, endpoint(c.resourceURL("containers", ref), "/secrets"), request, nil)
+	return c.containerSecretNotFound(ctx, ref, err)
+}
+
+// containerSecretNotFound replaces err with ErrContainerNotFound
+// if err is an HTTP 404 error and the container addressed by ref
+// does not exist. Barbican also responds with 404 if the secret
+// does not exist or is not part of the container.
+func (c *Client) containerSecretNotFound(ctx context.Context, ref string, err error) error {
+	var e xerror.Error
+	if !errors.As(err, &e) || e.Status() != http.StatusNotFound {
+		return err
+	}
+	if _, cErr := c.GetContainer(ctx, ref); errors.Is(cErr, xerror.ErrContainerNotFound) {
+		return cErr
+	}
+	return err
+}
+
+// ListContainers returns a new ContainerIterator over all
+// containers of the project.
+//
+// The containers are fetched page by page, so the iterator
+// may or may not reflect concurrent changes to Barbican.
+func (c *Client) ListContainers(ctx context.Context) (ContainerIterator, error) {
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan BarbicanContainer, 10)
+
+	go func() {
+		defer close(values)
+
+		var next string
+		const limit = 100 // We limit a listing page to 100. This is the maximum Barbican allows by default.
+		for {
+			reqURL := c.apiEndpoint("containers") + "?limit=" + fmt.Sprint(limit)
+			if next != "" {
+				reqURL = next
+			}
+
+			resp, err := c.client.HttpGet(ctx, reqURL, nil)
+			if err != nil {
+				cancel(fmt.Errorf("barbican: failed to list containers: %v", err))
+				break
+			}
+
+			var containers BarbicanContainersResponse
+			if err := json.Unmarshal(resp, &containers); err != nil {
+				cancel(fmt.Errorf("barbican: failed to list containers: failed to parse server response: %v", err))
+				break
+			}
+			for _, container := range containers.Containers {
+				select {
+				case values <- container:
+				case <-ctx.Done():
+					return
+				}
+			}
+			next = containers.Next
+			if next == "" || len(containers.Containers) == 0 {
+				break
+			}
+		}
+	}()
+	return &ContainerListIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
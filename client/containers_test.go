@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestContainerCreateRequest(t *testing.T) {
+	for i, test := range containerCreateRequestTests {
+		if request := test.Spec.CreateRequest(); !reflect.DeepEqual(request, test.Request) {
+			t.Fatalf("Test %d: got %+v - want %+v", i, request, test.Request)
+		}
+	}
+}
+
+func TestContainerConversion(t *testing.T) {
+	rsa := BarbicanContainer{
+		Name: "my-key-pair",
+		Type: ContainerTypeRSA,
+		SecretRefs: []ContainerSecretRef{
+			{Name: ContainerSecretPrivateKey, SecretRef: "private"},
+			{Name: ContainerSecretPublicKey, SecretRef: "public"},
+		},
+	}
+	spec, err := rsa.RSA()
+	if err != nil {
+		t.Fatalf("Failed to convert rsa container: %v", err)
+	}
+	if want := (RSAContainer{Name: "my-key-pair", PublicKeyRef: "public", PrivateKeyRef: "private"}); *spec != want {
+		t.Fatalf("Got %+v - want %+v", *spec, want)
+	}
+	if _, err = rsa.Certificate(); err == nil {
+		t.Fatal("Converted an rsa container to a certificate container")
+	}
+	if generic := rsa.Generic(); !reflect.DeepEqual(generic.SecretRefs, rsa.SecretRefs) {
+		t.Fatalf("Got secret refs %v - want %v", generic.SecretRefs, rsa.SecretRefs)
+	}
+
+	generic := BarbicanContainer{Type: ContainerTypeGeneric}
+	if _, err = generic.RSA(); err == nil {
+		t.Fatal("Converted a generic container to an rsa container")
+	}
+}
+
+func TestCreateContainer(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/containers", func(w http.ResponseWriter, r *http.Request) {
+		var request ContainerCreateRequest
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil {
+			writeFault(w, http.StatusBadRequest, "Bad Request", "invalid request")
+			return
+		}
+		if request.Type != ContainerTypeCertificate || len(request.SecretRefs) != 2 {
+			writeFault(w, http.StatusBadRequest, "Bad Request", "unexpected container")
+			return
+		}
+		writeJSON(w, http.StatusCreated, ContainerRefResponse{ContainerRef: server.URL + "/v1/containers/new"})
+	})
+	client := server.connect(t, nil)
+
+	container, err := client.CreateContainer(context.Background(), CertificateContainer{
+		Name:             "my-cert",
+		CertificateRef:   "certificate",
+		IntermediatesRef: "intermediates",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	if container.ContainerRef != server.URL+"/v1/containers/new" || container.Type != ContainerTypeCertificate || container.Name != "my-cert" {
+		t.Fatalf("Got unexpected container %+v", *container)
+	}
+}
+
+func TestContainerNotFound(t *testing.T) {
+	server := newTestServer(t)
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "Container not found.")
+	}
+	server.HandleFunc("/v1/containers/missing", notFound)
+	server.HandleFunc("/v1/containers/missing/secrets", notFound)
+	server.HandleFunc("/v1/containers/existing", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanContainer{ContainerRef: server.URL + "/v1/containers/existing", Type: ContainerTypeGeneric})
+	})
+	server.HandleFunc("/v1/containers/existing/secrets", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "Secret not found.")
+	})
+	client := server.connect(t, nil)
+
+	ctx := context.Background()
+	secret := ContainerSecretRef{Name: "key", SecretRef: server.URL + "/v1/secrets/missing"}
+	for i, test := range []struct {
+		Err      error
+		NotFound bool // Whether the error should be ErrContainerNotFound
+	}{
+		{Err: client.DeleteContainer(ctx, "missing"), NotFound: true},
+		{Err: client.AddContainerSecret(ctx, "missing", secret), NotFound: true},
+		{Err: client.RemoveContainerSecret(ctx, "missing", secret), NotFound: true},
+		{Err: func() error { _, err := client.GetContainer(ctx, "missing"); return err }(), NotFound: true},
+
+		// The container exists but the secret does not.
+		{Err: client.AddContainerSecret(ctx, "existing", secret), NotFound: false},
+		{Err: client.RemoveContainerSecret(ctx, "existing", secret), NotFound: false},
+	} {
+		if test.Err == nil {
+			t.Fatalf("Test %d: request succeeded", i)
+		}
+		if notFound := errors.Is(test.Err, xerror.ErrContainerNotFound); notFound != test.NotFound {
+			t.Fatalf("Test %d: got error '%v' - container not found: %v", i, test.Err, test.NotFound)
+		}
+		if !errors.Is(test.Err, xerror.NotFound) {
+			t.Fatalf("Test %d: got error '%v' - want a not found error", i, test.Err)
+		}
+	}
+}
+
+var containerCreateRequestTests = []struct {
+	Spec    ContainerSpec
+	Request ContainerCreateRequest
+}{
+	{ // 0
+		Spec: GenericContainer{
+			Name:       "my-secrets",
+			SecretRefs: []ContainerSecretRef{{Name: "a", SecretRef: "ref-a"}},
+		},
+		Request: ContainerCreateRequest{
+			Name:       "my-secrets",
+			Type:       ContainerTypeGeneric,
+			SecretRefs: []ContainerSecretRef{{Name: "a", SecretRef: "ref-a"}},
+		},
+	},
+	{ // 1 - Optional refs are omitted
+		Spec: RSAContainer{
+			Name:          "my-key-pair",
+			PublicKeyRef:  "public",
+			PrivateKeyRef: "private",
+		},
+		Request: ContainerCreateRequest{
+			Name: "my-key-pair",
+			Type: ContainerTypeRSA,
+			SecretRefs: []ContainerSecretRef{
+				{Name: ContainerSecretPublicKey, SecretRef: "public"},
+				{Name: ContainerSecretPrivateKey, SecretRef: "private"},
+			},
+		},
+	},
+	{ // 2
+		Spec: CertificateContainer{
+			Name:                    "my-cert",
+			CertificateRef:          "certificate",
+			PrivateKeyRef:           "private",
+			PrivateKeyPassphraseRef: "passphrase",
+			IntermediatesRef:        "intermediates",
+		},
+		Request: ContainerCreateRequest{
+			Name: "my-cert",
+			Type: ContainerTypeCertificate,
+			SecretRefs: []ContainerSecretRef{
+				{Name: ContainerSecretCertificate, SecretRef: "certificate"},
+				{Name: ContainerSecretPrivateKey, SecretRef: "private"},
+				{Name: ContainerSecretPrivateKeyPassphrase, SecretRef: "passphrase"},
+				{Name: ContainerSecretIntermediates, SecretRef: "intermediates"},
+			},
+		},
+	},
+}
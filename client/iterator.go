@@ -34,3 +34,105 @@ func (i *ListIterator) Close() error {
 	// i.cancel(context.Canceled)
 	return context.Cause(i.ctx)
 }
+
+type SecretIterator interface {
+	Next() (*BarbicanSecret, bool)
+	Close() error
+}
+
+type SecretListIterator struct {
+	ch     <-chan BarbicanSecret
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next secret, if any.
+//
+// It returns true if and only if there is a new secret
+// available. If there are no more secrets or an error
+// has been encountered, Next returns false.
+func (i *SecretListIterator) Next() (*BarbicanSecret, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of secrets.
+func (i *SecretListIterator) Close() error {
+	return context.Cause(i.ctx)
+}
+
+type ContainerIterator interface {
+	Next() (*BarbicanContainer, bool)
+	Close() error
+}
+
+type ContainerListIterator struct {
+	ch     <-chan BarbicanContainer
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next container, if any.
+//
+// It returns true if and only if there is a new container
+// available. If there are no more containers or an error
+// has been encountered, Next returns false.
+func (i *ContainerListIterator) Next() (*BarbicanContainer, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of containers.
+func (i *ContainerListIterator) Close() error {
+	return context.Cause(i.ctx)
+}
+
+type OrderIterator interface {
+	Next() (*BarbicanOrder, bool)
+	Close() error
+}
+
+type OrderListIterator struct {
+	ch     <-chan BarbicanOrder
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next order, if any.
+//
+// It returns true if and only if there is a new order
+// available. If there are no more orders or an error
+// has been encountered, Next returns false.
+func (i *OrderListIterator) Next() (*BarbicanOrder, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of orders.
+func (i *OrderListIterator) Close() error {
+	return context.Cause(i.ctx)
+}
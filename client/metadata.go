@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// GetSecretWithMetadata returns the secret addressed by the
+// given secret reference or UUID including its user metadata.
+func (c *Client) GetSecretWithMetadata(ctx context.Context, id string) (*BarbicanSecret, error) {
+	secret, err := c.GetSecretByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Metadata, err = c.GetSecretMetadata(ctx, id); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// GetSecretMetadata returns the user metadata of the secret
+// addressed by the given secret reference or UUID.
+func (c *Client) GetSecretMetadata(ctx context.Context, id string) (map[string]string, error) {
+	resp, err := c.client.HttpGet(ctx, c.metadataURL(id), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrKeyNotFound)
+	}
+
+	var response SecretMetadata
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch metadata of '%s': failed to parse server response: %v", id, err)
+	}
+	if response.Metadata == nil {
+		response.Metadata = map[string]string{}
+	}
+	return response.Metadata, nil
+}
+
+// PutSecretMetadata replaces the user metadata of the secret
+// addressed by id with the given metadata. Keys are stored in
+// lower case by Barbican.
+func (c *Client) PutSecretMetadata(ctx context.Context, id string, metadata map[string]string) error {
+	request, err := json.Marshal(SecretMetadata{Metadata: metadata})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPut(ctx, c.metadataURL(id), request, nil)
+	return notFound(err, xerror.ErrKeyNotFound)
+}
+
+// AddSecretMetadatum adds a new key/value pair to the user
+// metadata of the secret addressed by id. It returns
+// ErrMetadatumExists if the key is already present.
+func (c *Client) AddSecretMetadatum(ctx context.Context, id, key, value string) error {
+	request, err := json.Marshal(SecretMetadatum{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPost(ctx, c.metadataURL(id), request, nil)
+	var e xerror.Error
+	if errors.As(err, &e) && e.Status() == http.StatusConflict {
+		return xerror.ErrMetadatumExists.WithRequest(e.Method(), e.URL(), e.RequestID())
+	}
+	return notFound(err, xerror.ErrKeyNotFound)
+}
+
+// UpdateSecretMetadatum updates the value of an existing key
+// of the user metadata of the secret addressed by id. It returns
+// ErrMetadatumNotFound if the key is not present and
+// ErrKeyNotFound if the secret does not exist.
+func (c *Client) UpdateSecretMetadatum(ctx context.Context, id, key, value string) error {
+	request, err := json.Marshal(SecretMetadatum{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPut(ctx, endpoint(c.metadataURL(id), key), request, nil)
+	return c.metadatumNotFound(ctx, id, err)
+}
+
+// DeleteSecretMetadatum removes the given key from the user
+// metadata of the secret addressed by id. It returns
+// ErrMetadatumNotFound if the key is not present and
+// ErrKeyNotFound if the secret does not exist.
+func (c *Client) DeleteSecretMetadatum(ctx context.Context, id, key string) error {
+	_, err := c.client.HttpDelete(ctx, endpoint(c.metadataURL(id), key), nil, nil)
+	return c.metadatumNotFound(ctx, id, err)
+}
+
+// metadatumNotFound replaces an HTTP 404 error with ErrKeyNotFound
+// if the secret addressed by id does not exist, and with
+// ErrMetadatumNotFound otherwise. Barbican responds with 404
+// in both cases.
+func (c *Client) metadatumNotFound(ctx context.Context, id string, err error) error {
+	var e xerror.Error
+	if !errors.As(err, &e) || e.Status() != http.StatusNotFound {
+		return err
+	}
+	if _, sErr := c.GetSecretByID(ctx, id); errors.Is(sErr, xerror.ErrKeyNotFound) {
+		return sErr
+	}
+	return notFound(err, xerror.ErrMetadatumNotFound)
+}
+
+func (c *Client) metadataURL(id string) string {
+	return endpoint(c.resourceURL("secrets", id), "/metadata")
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestSecretMetadataErrors(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets/existing", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanSecret{SecretRef: server.URL + "/v1/secrets/existing"})
+	})
+	server.HandleFunc("/v1/secrets/existing/metadata", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusConflict, "Conflict", "Metadatum with key 'purpose' already exists.")
+	})
+	server.HandleFunc("/v1/secrets/existing/metadata/", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "Secret metadatum not found.")
+	})
+	server.HandleFunc("/v1/secrets/missing/", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "Secret not found.")
+	})
+	client := server.connect(t, nil)
+
+	ctx := context.Background()
+	for i, test := range []struct {
+		Err  error
+		Want error
+	}{
+		{Err: client.AddSecretMetadatum(ctx, "existing", "purpose", "test"), Want: xerror.ErrMetadatumExists},              // 0
+		{Err: client.UpdateSecretMetadatum(ctx, "existing", "purpose", "test"), Want: xerror.ErrMetadatumNotFound},         // 1
+		{Err: client.DeleteSecretMetadatum(ctx, "existing", "purpose"), Want: xerror.ErrMetadatumNotFound},                 // 2
+		{Err: client.AddSecretMetadatum(ctx, "missing", "purpose", "test"), Want: xerror.ErrKeyNotFound},                   // 3
+		{Err: client.UpdateSecretMetadatum(ctx, "missing", "purpose", "test"), Want: xerror.ErrKeyNotFound},                // 4
+		{Err: client.DeleteSecretMetadatum(ctx, "missing", "purpose"), Want: xerror.ErrKeyNotFound},                        // 5
+		{Err: client.PutSecretMetadata(ctx, "missing", map[string]string{"purpose": "test"}), Want: xerror.ErrKeyNotFound}, // 6
+	} {
+		if !errors.Is(test.Err, test.Want) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, test.Err, test.Want)
+		}
+	}
+}
+
+func TestGetSecretMetadata(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets/empty/metadata", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"metadata": nil})
+	})
+	server.HandleFunc("/v1/secrets/tagged/metadata", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, SecretMetadata{Metadata: map[string]string{"purpose": "test"}})
+	})
+	client := server.connect(t, nil)
+
+	metadata, err := client.GetSecretMetadata(context.Background(), "empty")
+	if err != nil {
+		t.Fatalf("Failed to fetch metadata: %v", err)
+	}
+	if metadata == nil || len(metadata) != 0 {
+		t.Fatalf("Got %v - want an empty, non-nil map", metadata)
+	}
+
+	metadata, err = client.GetSecretMetadata(context.Background(), "tagged")
+	if err != nil {
+		t.Fatalf("Failed to fetch metadata: %v", err)
+	}
+	if metadata["purpose"] != "test" {
+		t.Fatalf("Got %v - want purpose 'test'", metadata)
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// SecretType is the type of a Barbican secret.
+type SecretType string
+
+const (
+	SecretTypeSymmetric   SecretType = "symmetric"
+	SecretTypePublic      SecretType = "public"
+	SecretTypePrivate     SecretType = "private"
+	SecretTypePassphrase  SecretType = "passphrase"
+	SecretTypeCertificate SecretType = "certificate"
+	SecretTypeOpaque      SecretType = "opaque"
+)
+
+// Payload content types supported by Barbican.
+const (
+	ContentTypeOctetStream = "application/octet-stream"
+	ContentTypeTextPlain   = "text/plain"
+)
+
+// CreateMode controls how creating a secret deals with
+// existing secrets with the same name. Barbican itself
+// does not require secret names to be unique.
+type CreateMode int
+
+const (
+	// CreateIfAbsent creates the secret only if no secret with
+	// the same name exists. Otherwise, xerror.ErrKeyExists is
+	// returned.
+	//
+	// The guarantee is best-effort: Barbican has no conditional
+	// create. Hence, the secret is created and the secrets with
+	// the same name are looked up afterwards. If another one has
+	// been created before - ordered by creation time and, within
+	// the same second, by ID - the new secret is deleted again.
+	// Concurrent creators agree on this order, so exactly one of
+	// them succeeds as long as all see each other's secrets. A
+	// duplicate remains if deleting a losing secret fails.
+	CreateIfAbsent CreateMode = iota
+
+	// CreateOrReplace creates the secret and then deletes all
+	// other secrets with the same name, including their metadata,
+	// ACLs and consumers.
+	CreateOrReplace
+
+	// CreateAllowDuplicates creates the secret regardless
+	// of existing secrets with the same name.
+	CreateAllowDuplicates
+)
+
+// SecretOptions holds the metadata of a secret to be created.
+// Zero values are not sent to Barbican.
+type SecretOptions struct {
+	SecretType         SecretType
+	Algorithm          string
+	BitLength          int
+	Mode               string
+	Expiration         time.Time
+	PayloadContentType string
+
+	// CreateMode is not sent to Barbican but controls how
+	// existing secrets with the same name are handled.
+	CreateMode CreateMode
+}
+
+// SecretOption customizes the secret created by CreateWithOptions.
+type SecretOption func(*SecretOptions)
+
+// WithSecretType sets the type of the secret. Defaults to opaque.
+func WithSecretType(secretType SecretType) SecretOption {
+	return func(o *SecretOptions) { o.SecretType = secretType }
+}
+
+// WithAlgorithm sets the algorithm of the secret, e.g. aes.
+func WithAlgorithm(algorithm string) SecretOption {
+	return func(o *SecretOptions) { o.Algorithm = algorithm }
+}
+
+// WithBitLength sets the bit length of the secret, e.g. 256.
+func WithBitLength(bitLength int) SecretOption {
+	return func(o *SecretOptions) { o.BitLength = bitLength }
+}
+
+// WithMode sets the mode of the secret, e.g. cbc.
+func WithMode(mode string) SecretOption {
+	return func(o *SecretOptions) { o.Mode = mode }
+}
+
+// WithExpiration sets the time after which the secret
+// is no longer available.
+func WithExpiration(expiration time.Time) SecretOption {
+	return func(o *SecretOptions) { o.Expiration = expiration }
+}
+
+// WithPayloadContentType sets the media type of the payload.
+// Defaults to application/octet-stream. text/plain payloads
+// are sent as is, all others are base64 encoded.
+func WithPayloadContentType(contentType string) SecretOption {
+	return func(o *SecretOptions) { o.PayloadContentType = contentType }
+}
+
+// WithCreateMode sets how existing secrets with the same
+// name are handled. Defaults to CreateIfAbsent.
+func WithCreateMode(mode CreateMode) SecretOption {
+	return func(o *SecretOptions) { o.CreateMode = mode }
+}
+
+// NewSecretOptions returns the SecretOptions resulting
+// from applying opts to the defaults - an opaque secret
+// with an application/octet-stream payload.
+func NewSecretOptions(opts ...SecretOption) SecretOptions {
+	options := SecretOptions{
+		SecretType:         SecretTypeOpaque,
+		PayloadContentType: ContentTypeOctetStream,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// NewSecretCreateRequest returns the request creating a
+// secret with the given name, payload and options.
+func NewSecretCreateRequest(name string, value []byte, opts ...SecretOption) SecretCreateRequest {
+	options := NewSecretOptions(opts...)
+	request := SecretCreateRequest{
+		SecretType:         string(options.SecretType),
+		Name:               name,
+		Expiration:         formatExpiration(options.Expiration),
+		PayloadContentType: options.PayloadContentType,
+		Algorithm:          options.Algorithm,
+		BitLength:          options.BitLength,
+		Mode:               options.Mode,
+	}
+	if strings.HasPrefix(options.PayloadContentType, ContentTypeTextPlain) {
+		request.Payload = string(value)
+	} else {
+		request.Payload = base64.StdEncoding.EncodeToString(value)
+		request.PayloadContentEncoding = "base64"
+	}
+	return request
+}
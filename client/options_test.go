@@ -0,0 +1,81 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSecretOptions(t *testing.T) {
+	options := NewSecretOptions()
+	if want := (SecretOptions{SecretType: SecretTypeOpaque, PayloadContentType: ContentTypeOctetStream}); options != want {
+		t.Fatalf("Got default options %+v - want %+v", options, want)
+	}
+
+	options = NewSecretOptions(WithSecretType(SecretTypeSymmetric), WithCreateMode(CreateOrReplace), WithSecretType(SecretTypePassphrase))
+	if options.SecretType != SecretTypePassphrase || options.CreateMode != CreateOrReplace {
+		t.Fatalf("Got options %+v - want the last secret type and the create mode to be applied", options)
+	}
+}
+
+func TestNewSecretCreateRequest(t *testing.T) {
+	for i, test := range newSecretCreateRequestTests {
+		request := NewSecretCreateRequest(test.Name, []byte(test.Value), test.Options...)
+		if !reflect.DeepEqual(request, test.Request) {
+			t.Fatalf("Test %d: got %+v - want %+v", i, request, test.Request)
+		}
+	}
+}
+
+var newSecretCreateRequestTests = []struct {
+	Name    string
+	Value   string
+	Options []SecretOption
+	Request SecretCreateRequest
+}{
+	{ // 0 - Defaults
+		Name:  "my-secret",
+		Value: "Hello World",
+		Request: SecretCreateRequest{
+			Name:                   "my-secret",
+			SecretType:             "opaque",
+			Payload:                "SGVsbG8gV29ybGQ=",
+			PayloadContentType:     "application/octet-stream",
+			PayloadContentEncoding: "base64",
+		},
+	},
+	{ // 1
+		Name:  "my-key",
+		Value: "0123456789abcdef0123456789abcdef",
+		Options: []SecretOption{
+			WithSecretType(SecretTypeSymmetric),
+			WithAlgorithm("aes"),
+			WithBitLength(256),
+			WithMode("cbc"),
+			WithExpiration(time.Date(2030, 1, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))),
+			WithCreateMode(CreateAllowDuplicates),
+		},
+		Request: SecretCreateRequest{
+			Name:                   "my-key",
+			SecretType:             "symmetric",
+			Algorithm:              "aes",
+			BitLength:              256,
+			Mode:                   "cbc",
+			Expiration:             "2030-01-01T00:00:00Z",
+			Payload:                "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+			PayloadContentType:     "application/octet-stream",
+			PayloadContentEncoding: "base64",
+		},
+	},
+	{ // 2 - text/plain payloads are not encoded
+		Name:    "my-passphrase",
+		Value:   "correct horse battery staple",
+		Options: []SecretOption{WithSecretType(SecretTypePassphrase), WithPayloadContentType("text/plain; charset=utf-8")},
+		Request: SecretCreateRequest{
+			Name:               "my-passphrase",
+			SecretType:         "passphrase",
+			Payload:            "correct horse battery staple",
+			PayloadContentType: "text/plain; charset=utf-8",
+		},
+	},
+}
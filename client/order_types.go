@@ -0,0 +1,86 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+const expirationFormat = "2006-01-02T15:04:05Z"
+
+// formatExpiration formats t as the UTC ISO 8601 timestamp
+// Barbican expects. The zero time is formatted as empty string.
+func formatExpiration(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(expirationFormat)
+}
+
+func (o KeyOrder) CreateRequest() OrderCreateRequest {
+	return OrderCreateRequest{
+		Type: OrderTypeKey,
+		Meta: OrderMeta{
+			Name:               o.Name,
+			Algorithm:          o.Algorithm,
+			BitLength:          o.BitLength,
+			Mode:               o.Mode,
+			PayloadContentType: o.PayloadContentType,
+			Expiration:         formatExpiration(o.Expiration),
+		},
+	}
+}
+
+func (o AsymmetricOrder) CreateRequest() OrderCreateRequest {
+	return OrderCreateRequest{
+		Type: OrderTypeAsymmetric,
+		Meta: OrderMeta{
+			Name:               o.Name,
+			Algorithm:          o.Algorithm,
+			BitLength:          o.BitLength,
+			PassPhrase:         o.PassPhrase,
+			PayloadContentType: o.PayloadContentType,
+			Expiration:         formatExpiration(o.Expiration),
+		},
+	}
+}
+
+func (o CertificateOrder) CreateRequest() OrderCreateRequest {
+	return OrderCreateRequest{
+		Type: OrderTypeCertificate,
+		Meta: OrderMeta{
+			Name:               o.Name,
+			RequestType:        o.RequestType,
+			SubjectDN:          o.SubjectDN,
+			SourceContainerRef: o.SourceContainerRef,
+			CAID:               RefID(o.CAID),
+			Profile:            o.Profile,
+			RequestData:        o.RequestData,
+		},
+	}
+}
+
+// ResultRef returns the reference of the secret or container
+// generated by the order. It is empty until the order is ACTIVE.
+func (o *BarbicanOrder) ResultRef() string {
+	if o.SecretRef != "" {
+		return o.SecretRef
+	}
+	return o.ContainerRef
+}
+
+// Err returns an error describing why the order failed,
+// or nil if the order is not in the ERROR state.
+func (o *BarbicanOrder) Err() error {
+	if o.Status != OrderStatusError {
+		return nil
+	}
+	code, err := strconv.Atoi(o.ErrorStatusCode)
+	if err != nil || code < 400 {
+		code = http.StatusInternalServerError
+	}
+	return xerror.NewError(code, fmt.Sprintf("barbican: order '%s' failed: %s", o.OrderRef, o.ErrorReason))
+}
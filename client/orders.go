@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// SubmitOrder submits a new order described by the given spec.
+// Barbican processes orders asynchronously, so the returned order
+// is usually still PENDING. Use WaitForOrder to wait until the
+// generated secret or container is available.
+func (c *Client) SubmitOrder(ctx context.Context, spec OrderSpec) (*BarbicanOrder, error) {
+	createRequest := spec.CreateRequest()
+	request, err := json.Marshal(createRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.HttpPost(ctx, c.apiEndpoint("orders"), request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OrderRefResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to submit %s order: failed to parse server response: %v", createRequest.Type, err)
+	}
+	return &BarbicanOrder{
+		Meta:     createRequest.Meta,
+		OrderRef: response.OrderRef,
+		Status:   OrderStatusPending,
+		Type:     createRequest.Type,
+	}, nil
+}
+
+// GetOrder returns the order addressed by the given order
+// reference or UUID.
+func (c *Client) GetOrder(ctx context.Context, ref string) (*BarbicanOrder, error) {
+	resp, err := c.client.HttpGet(ctx, c.resourceURL("orders", ref), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrOrderNotFound)
+	}
+
+	var order BarbicanOrder
+	if err := json.Unmarshal(resp, &order); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch order '%s': failed to parse order: %v", ref, err)
+	}
+	return &order, nil
+}
+
+// WaitForOrder polls the order addressed by ref every interval
+// until it is either ACTIVE or ERROR, or until ctx is done.
+// If interval is not positive, the order is polled every second.
+//
+// If the order ends up in the ERROR state, WaitForOrder returns
+// the order together with an error describing the failure.
+func (c *Client) WaitForOrder(ctx context.Context, ref string, interval time.Duration) (*BarbicanOrder, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		order, err := c.GetOrder(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		switch order.Status {
+		case OrderStatusActive:
+			return order, nil
+		case OrderStatusError:
+			return order, order.Err()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+}
+
+// DeleteOrder deletes the order addressed by the given order
+// reference or UUID. The generated secret or container, if any,
+// is not deleted.
+func (c *Client) DeleteOrder(ctx context.Context, ref string) error {
+	_, err := c.client.HttpDelete(ctx, c.resourceURL("orders", ref), nil, nil)
+	return notFound(err, xerror.ErrOrderNotFound)
+}
+
+// ListOrders returns a new OrderIterator over all orders
+// of the project.
+//
+// The orders are fetched page by page, so the iterator
+// may or may not reflect concurrent changes to Barbican.
+func (c *Client) ListOrders(ctx context.Context) (OrderIterator, error) {
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan BarbicanOrder, 10)
+
+	go func() {
+		defer close(values)
+
+		var next string
+		const limit = 100 // We limit a listing page to 100. This is the maximum Barbican allows by default.
+		for {
+			reqURL := c.apiEndpoint("orders") + "?limit=" + fmt.Sprint(limit)
+			if next != "" {
+				reqURL = next
+			}
+
+			resp, err := c.client.HttpGet(ctx, reqURL, nil)
+			if err != nil {
+				cancel(fmt.Errorf("barbican: failed to list orders: %v", err))
+				break
+			}
+
+			var orders BarbicanOrdersResponse
+			if err := json.Unmarshal(resp, &orders); err != nil {
+				cancel(fmt.Errorf("barbican: failed to list orders: failed to parse server response: %v", err))
+				break
+			}
+			for _, order := range orders.Orders {
+				select {
+				case values <- order:
+				case <-ctx.Done():
+					return
+				}
+			}
+			next = orders.Next
+			if next == "" || len(orders.Orders) == 0 {
+				break
+			}
+		}
+	}()
+	return &OrderListIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
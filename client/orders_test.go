@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestOrderCreateRequest(t *testing.T) {
+	for i, test := range orderCreateRequestTests {
+		if request := test.Spec.CreateRequest(); !reflect.DeepEqual(request, test.Request) {
+			t.Fatalf("Test %d: got %+v - want %+v", i, request, test.Request)
+		}
+	}
+}
+
+func TestOrderErr(t *testing.T) {
+	for i, test := range orderErrTests {
+		err := test.Order.Err()
+		if (err == nil) != (test.Code == 0) {
+			t.Fatalf("Test %d: got error '%v' - want status code %d", i, err, test.Code)
+		}
+		if err == nil {
+			continue
+		}
+
+		var e xerror.Error
+		if !errors.As(err, &e) {
+			t.Fatalf("Test %d: got error '%v' of type %T - want %T", i, err, err, e)
+		}
+		if e.Status() != test.Code {
+			t.Fatalf("Test %d: got status code %d - want %d", i, e.Status(), test.Code)
+		}
+	}
+}
+
+func TestOrderResultRef(t *testing.T) {
+	if ref := (&BarbicanOrder{SecretRef: "secret"}).ResultRef(); ref != "secret" {
+		t.Fatalf("Got '%s' - want 'secret'", ref)
+	}
+	if ref := (&BarbicanOrder{ContainerRef: "container"}).ResultRef(); ref != "container" {
+		t.Fatalf("Got '%s' - want 'container'", ref)
+	}
+	if ref := (&BarbicanOrder{Status: OrderStatusPending}).ResultRef(); ref != "" {
+		t.Fatalf("Got '%s' for a pending order - want ''", ref)
+	}
+}
+
+func TestWaitForOrder(t *testing.T) {
+	var polls atomic.Int32
+	server := newTestServer(t)
+	server.HandleFunc("/v1/orders/pending", func(w http.ResponseWriter, r *http.Request) {
+		order := BarbicanOrder{OrderRef: server.URL + "/v1/orders/pending", Status: OrderStatusPending}
+		if polls.Add(1) >= 3 {
+			order.Status, order.SecretRef = OrderStatusActive, server.URL+"/v1/secrets/generated"
+		}
+		writeJSON(w, http.StatusOK, order)
+	})
+	server.HandleFunc("/v1/orders/failed", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanOrder{
+			OrderRef:        server.URL + "/v1/orders/failed",
+			Status:          OrderStatusError,
+			ErrorStatusCode: "400",
+			ErrorReason:     "Invalid bit length.",
+		})
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	order, err := client.WaitForOrder(ctx, "pending", time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for order: %v", err)
+	}
+	if order.Status != OrderStatusActive || order.ResultRef() != server.URL+"/v1/secrets/generated" {
+		t.Fatalf("Got unexpected order %+v", *order)
+	}
+	if n := polls.Load(); n != 3 {
+		t.Fatalf("Got %d polls - want 3", n)
+	}
+
+	order, err = client.WaitForOrder(ctx, "failed", time.Millisecond)
+	if err == nil || order == nil || order.Status != OrderStatusError {
+		t.Fatalf("Got order %v and error '%v' - want the failed order and an error", order, err)
+	}
+
+	polls.Store(-100)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err = client.WaitForOrder(ctx, "pending", time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Got error '%v' - want '%v'", err, context.DeadlineExceeded)
+	}
+
+	if _, err = client.WaitForOrder(context.Background(), "missing", time.Millisecond); !errors.Is(err, xerror.ErrOrderNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrOrderNotFound)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/orders/existing", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := server.connect(t, nil)
+
+	if err := client.DeleteOrder(context.Background(), server.URL+"/v1/orders/existing"); err != nil {
+		t.Fatalf("Failed to delete order: %v", err)
+	}
+	if err := client.DeleteOrder(context.Background(), "missing"); !errors.Is(err, xerror.ErrOrderNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrOrderNotFound)
+	}
+}
+
+var orderCreateRequestTests = []struct {
+	Spec    OrderSpec
+	Request OrderCreateRequest
+}{
+	{ // 0
+		Spec: KeyOrder{
+			Name:       "my-key",
+			Algorithm:  "aes",
+			BitLength:  256,
+			Mode:       "cbc",
+			Expiration: time.Date(2030, 1, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		},
+		Request: OrderCreateRequest{
+			Type: OrderTypeKey,
+			Meta: OrderMeta{
+				Name:       "my-key",
+				Algorithm:  "aes",
+				BitLength:  256,
+				Mode:       "cbc",
+				Expiration: "2030-01-01T00:00:00Z",
+			},
+		},
+	},
+	{ // 1
+		Spec: AsymmetricOrder{
+			Name:       "my-key-pair",
+			Algorithm:  "rsa",
+			BitLength:  2048,
+			PassPhrase: "secret",
+		},
+		Request: OrderCreateRequest{
+			Type: OrderTypeAsymmetric,
+			Meta: OrderMeta{
+				Name:       "my-key-pair",
+				Algorithm:  "rsa",
+				BitLength:  2048,
+				PassPhrase: "secret",
+			},
+		},
+	},
+	{ // 2 - CA references are reduced to the UUID
+		Spec: CertificateOrder{
+			Name:               "my-cert",
+			RequestType:        CertificateRequestStoredKey,
+			SubjectDN:          "CN=example.com",
+			SourceContainerRef: "http://localhost:9311/v1/containers/c1",
+			CAID:               "http://localhost:9311/v1/cas/ca1",
+		},
+		Request: OrderCreateRequest{
+			Type: OrderTypeCertificate,
+			Meta: OrderMeta{
+				Name:               "my-cert",
+				RequestType:        CertificateRequestStoredKey,
+				SubjectDN:          "CN=example.com",
+				SourceContainerRef: "http://localhost:9311/v1/containers/c1",
+				CAID:               "ca1",
+			},
+		},
+	},
+}
+
+var orderErrTests = []struct {
+	Order BarbicanOrder
+	Code  int // 0 means no error
+}{
+	{Order: BarbicanOrder{Status: OrderStatusPending}},                                    // 0
+	{Order: BarbicanOrder{Status: OrderStatusActive}},                                     // 1
+	{Order: BarbicanOrder{Status: OrderStatusError, ErrorStatusCode: "400"}, Code: 400},   // 2
+	{Order: BarbicanOrder{Status: OrderStatusError, ErrorStatusCode: "200"}, Code: 500},   // 3 - Not an error status code
+	{Order: BarbicanOrder{Status: OrderStatusError, ErrorStatusCode: "error"}, Code: 500}, // 4
+	{Order: BarbicanOrder{Status: OrderStatusError}, Code: 500},                           // 5
+}
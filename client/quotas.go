@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// GetQuotas returns the effective quotas of the project
+// the connection is scoped to.
+func (c *Client) GetQuotas(ctx context.Context) (*Quotas, error) {
+	resp, err := c.client.HttpGet(ctx, c.apiEndpoint("quotas"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response QuotasResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch quotas: failed to parse server response: %v", err)
+	}
+	return &response.Quotas, nil
+}
+
+// ListProjectQuotas returns the quotas of all projects
+// with project-specific quotas. It requires the Barbican
+// service admin role.
+func (c *Client) ListProjectQuotas(ctx context.Context) ([]ProjectQuotasEntry, error) {
+	params := url.Values{"limit": {fmt.Sprint(DefaultPageSize)}}
+	reqURL := c.apiEndpoint("project-quotas")
+
+	var quotas []ProjectQuotasEntry
+	for {
+		resp, err := c.client.HttpGet(ctx, reqURL, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var response ProjectQuotasListResponse
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return nil, fmt.Errorf("barbican: failed to list project quotas: failed to parse server response: %v", err)
+		}
+		quotas = append(quotas, response.ProjectQuotas...)
+		if response.Next == "" || len(response.ProjectQuotas) == 0 {
+			return quotas, nil
+		}
+		reqURL, params = response.Next, nil
+	}
+}
+
+// GetProjectQuotas returns the quotas configured for the given
+// project. It returns ErrProjectQuotasNotFound if the project
+// has no project-specific quotas. It requires the Barbican
+// service admin role.
+func (c *Client) GetProjectQuotas(ctx context.Context, projectID string) (*ProjectQuotas, error) {
+	resp, err := c.client.HttpGet(ctx, c.apiEndpoint("project-quotas", projectID), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrProjectQuotasNotFound)
+	}
+
+	var response ProjectQuotasResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch quotas of project '%s': failed to parse server response: %v", projectID, err)
+	}
+	return &response.ProjectQuotas, nil
+}
+
+// SetProjectQuotas replaces the quotas configured for the
+// given project. It requires the Barbican service admin role.
+func (c *Client) SetProjectQuotas(ctx context.Context, projectID string, quotas ProjectQuotas) error {
+	request, err := json.Marshal(ProjectQuotasRequest{ProjectQuotas: quotas})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.HttpPut(ctx, c.apiEndpoint("project-quotas", projectID), request, nil)
+	return err
+}
+
+// DeleteProjectQuotas removes the quotas configured for the
+// given project, such that the default quotas apply again.
+// It requires the Barbican service admin role.
+func (c *Client) DeleteProjectQuotas(ctx context.Context, projectID string) error {
+	_, err := c.client.HttpDelete(ctx, c.apiEndpoint("project-quotas", projectID), nil, nil)
+	return notFound(err, xerror.ErrProjectQuotasNotFound)
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SortDirection is the direction of a sort key.
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// Secret sort keys supported by Barbican.
+const (
+	SortByCreated    = "created"
+	SortByUpdated    = "updated"
+	SortByExpiration = "expiration"
+	SortByName       = "name"
+	SortByAlgorithm  = "algorithm"
+	SortByBitLength  = "bit_length"
+	SortByMode       = "mode"
+	SortBySecretType = "secret_type"
+	SortByStatus     = "status"
+)
+
+// SortKey orders a listing by the given key, e.g. SortByCreated.
+// The direction defaults to SortAsc.
+type SortKey struct {
+	Key       string
+	Direction SortDirection
+}
+
+// TimeRange restricts a timestamp to the interval [From, To).
+// Zero bounds are ignored.
+type TimeRange struct {
+	From time.Time // Inclusive lower bound.
+	To   time.Time // Exclusive upper bound.
+}
+
+// IsZero reports whether the range does not restrict anything.
+func (r TimeRange) IsZero() bool { return r.From.IsZero() && r.To.IsZero() }
+
+// Contains reports whether t lies within the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.From.IsZero() && t.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && !t.Before(r.To) {
+		return false
+	}
+	return true
+}
+
+// query returns the Barbican comparison filter of the range,
+// e.g. "gte:2023-01-01T00:00:00Z,lt:2024-01-01T00:00:00Z".
+func (r TimeRange) query() string {
+	var filters []string
+	if !r.From.IsZero() {
+		filters = append(filters, "gte:"+r.From.UTC().Format(expirationFormat))
+	}
+	if !r.To.IsZero() {
+		filters = append(filters, "lt:"+r.To.UTC().Format(expirationFormat))
+	}
+	return strings.Join(filters, ",")
+}
+
+// SecretFilter selects and orders the secrets returned by
+// ListSecretsWithFilter. Zero values do not filter.
+type SecretFilter struct {
+	Name       string
+	Algorithm  string
+	Mode       string
+	BitLength  int
+	SecretType SecretType
+
+	// ACLOnly restricts the listing to secrets the user
+	// has been granted read access to via an ACL.
+	ACLOnly bool
+
+	Created    TimeRange
+	Updated    TimeRange
+	Expiration TimeRange
+
+	// Sort orders the secrets by the given keys. Barbican
+	// orders by creation time by default.
+	Sort []SortKey
+
+	// PageSize is the number of secrets fetched per request.
+	// Defaults to DefaultPageSize.
+	PageSize int
+}
+
+// DefaultPageSize is the number of resources
+// fetched per listing request by default.
+const DefaultPageSize = 100
+
+// Query returns the Barbican query parameters of the filter,
+// excluding the page size and offset.
+func (f *SecretFilter) Query() url.Values {
+	params := url.Values{}
+	if f.Name != "" {
+		params.Set("name", f.Name)
+	}
+	if f.Algorithm != "" {
+		params.Set("alg", f.Algorithm)
+	}
+	if f.Mode != "" {
+		params.Set("mode", f.Mode)
+	}
+	if f.BitLength != 0 {
+		params.Set("bits", fmt.Sprint(f.BitLength))
+	}
+	if f.SecretType != "" {
+		params.Set("secret_type", string(f.SecretType))
+	}
+	if f.ACLOnly {
+		params.Set("acl_only", "true")
+	}
+	if !f.Created.IsZero() {
+		params.Set("created", f.Created.query())
+	}
+	if !f.Updated.IsZero() {
+		params.Set("updated", f.Updated.query())
+	}
+	if !f.Expiration.IsZero() {
+		params.Set("expiration", f.Expiration.query())
+	}
+	if len(f.Sort) > 0 {
+		keys := make([]string, 0, len(f.Sort))
+		for _, key := range f.Sort {
+			direction := key.Direction
+			if direction == "" {
+				direction = SortAsc
+			}
+			keys = append(keys, key.Key+":"+string(direction))
+		}
+		params.Set("sort", strings.Join(keys, ","))
+	}
+	return params
+}
+
+// ParseTime parses a Barbican timestamp, e.g. the
+// creation time of a secret. Barbican timestamps are
+// in UTC and may or may not carry a "Z" suffix.
+func ParseTime(s string) (time.Time, error) {
+	return time.Parse("2006-01-02T15:04:05.999999999", strings.TrimSuffix(s, "Z"))
+}
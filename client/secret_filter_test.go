@@ -0,0 +1,96 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSecretFilterQuery(t *testing.T) {
+	for i, test := range secretFilterQueryTests {
+		if query := test.Filter.Query().Encode(); query != test.Query {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, query, test.Query)
+		}
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	r := TimeRange{From: date(2023, 1, 1), To: date(2024, 1, 1)}
+	for i, test := range []struct {
+		Time     time.Time
+		Contains bool
+	}{
+		{Time: date(2022, 12, 31), Contains: false},
+		{Time: date(2023, 1, 1), Contains: true}, // From is inclusive
+		{Time: date(2023, 6, 1), Contains: true},
+		{Time: date(2024, 1, 1), Contains: false}, // To is exclusive
+	} {
+		if contains := r.Contains(test.Time); contains != test.Contains {
+			t.Fatalf("Test %d: got %v - want %v", i, contains, test.Contains)
+		}
+	}
+	if !(TimeRange{}).Contains(date(2023, 1, 1)) {
+		t.Fatal("Empty time range does not contain a time")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for i, s := range []string{"2023-01-01T00:00:00", "2023-01-01T00:00:00Z", "2023-01-01T00:00:00.000000"} {
+		v, err := ParseTime(s)
+		if err != nil {
+			t.Fatalf("Test %d: failed to parse '%s': %v", i, s, err)
+		}
+		if !v.Equal(date(2023, 1, 1)) {
+			t.Fatalf("Test %d: got %v - want %v", i, v, date(2023, 1, 1))
+		}
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var secretFilterQueryTests = []struct {
+	Filter SecretFilter
+	Query  string
+}{
+	{ // 0
+		Filter: SecretFilter{},
+		Query:  "",
+	},
+	{ // 1 - PageSize is not part of the query
+		Filter: SecretFilter{Name: "my-key", PageSize: 10},
+		Query:  "name=my-key",
+	},
+	{ // 2
+		Filter: SecretFilter{
+			Algorithm:  "aes",
+			Mode:       "cbc",
+			BitLength:  256,
+			SecretType: "symmetric",
+			ACLOnly:    true,
+		},
+		Query: "acl_only=true&alg=aes&bits=256&mode=cbc&secret_type=symmetric",
+	},
+	{ // 3
+		Filter: SecretFilter{
+			Created: TimeRange{From: date(2023, 1, 1), To: date(2024, 1, 1)},
+		},
+		Query: "created=gte%3A2023-01-01T00%3A00%3A00Z%2Clt%3A2024-01-01T00%3A00%3A00Z",
+	},
+	{ // 4 - Timestamps are converted to UTC
+		Filter: SecretFilter{
+			Updated:    TimeRange{From: time.Date(2023, 1, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))},
+			Expiration: TimeRange{To: date(2025, 1, 1)},
+		},
+		Query: "expiration=lt%3A2025-01-01T00%3A00%3A00Z&updated=gte%3A2023-01-01T00%3A00%3A00Z",
+	},
+	{ // 5
+		Filter: SecretFilter{
+			Sort: []SortKey{
+				{Key: SortByCreated, Direction: SortDesc},
+				{Key: SortByName},
+			},
+		},
+		Query: "sort=created%3Adesc%2Cname%3Aasc",
+	},
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// ListSecretStores returns the secret stores available
+// in Barbican. It returns ErrSecretStoreNotFound if the
+// deployment does not have multiple backends enabled.
+func (c *Client) ListSecretStores(ctx context.Context) ([]SecretStore, error) {
+	resp, err := c.client.HttpGet(ctx, c.apiEndpoint("secret-stores"), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrSecretStoreNotFound)
+	}
+
+	var response SecretStoresResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to list secret stores: failed to parse server response: %v", err)
+	}
+	return response.SecretStores, nil
+}
+
+// GetSecretStore returns the secret store addressed
+// by the given secret store reference or UUID.
+func (c *Client) GetSecretStore(ctx context.Context, ref string) (*SecretStore, error) {
+	return c.getSecretStore(ctx, c.resourceURL("secret-stores", ref))
+}
+
+// GetGlobalDefaultSecretStore returns the secret store used
+// for projects without a preferred secret store.
+func (c *Client) GetGlobalDefaultSecretStore(ctx context.Context) (*SecretStore, error) {
+	return c.getSecretStore(ctx, c.apiEndpoint("secret-stores/global-default"))
+}
+
+// GetPreferredSecretStore returns the preferred secret store
+// of the project, where new secrets of the project are stored.
+// It returns ErrSecretStoreNotFound if the project has no
+// preferred secret store.
+func (c *Client) GetPreferredSecretStore(ctx context.Context) (*SecretStore, error) {
+	return c.getSecretStore(ctx, c.apiEndpoint("secret-stores/preferred"))
+}
+
+// SetPreferredSecretStore makes the secret store addressed by
+// the given secret store reference or UUID the preferred secret
+// store of the project. Existing secrets are not moved.
+//
+// Barbican only allows project administrators to change the
+// preferred secret store.
+func (c *Client) SetPreferredSecretStore(ctx context.Context, ref string) error {
+	_, err := c.client.HttpPost(ctx, endpoint(c.resourceURL("secret-stores", ref), "/preferred"), nil, nil)
+	return notFound(err, xerror.ErrSecretStoreNotFound)
+}
+
+// UnsetPreferredSecretStore removes the preferred secret store
+// setting of the project, such that new secrets are stored in
+// the global default secret store again. The ref must address
+// the current preferred secret store.
+func (c *Client) UnsetPreferredSecretStore(ctx context.Context, ref string) error {
+	_, err := c.client.HttpDelete(ctx, endpoint(c.resourceURL("secret-stores", ref), "/preferred"), nil, nil)
+	return notFound(err, xerror.ErrSecretStoreNotFound)
+}
+
+func (c *Client) getSecretStore(ctx context.Context, reqURL string) (*SecretStore, error) {
+	resp, err := c.client.HttpGet(ctx, reqURL, nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrSecretStoreNotFound)
+	}
+
+	var store SecretStore
+	if err := json.Unmarshal(resp, &store); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch secret store: failed to parse server response: %v", err)
+	}
+	return &store, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestSecretStores(t *testing.T) {
+	server := newTestServer(t)
+	store := SecretStore{Name: "hsm", SecretStoreRef: server.URL + "/v1/secret-stores/hsm", Status: "ACTIVE"}
+	server.HandleFunc("/v1/secret-stores", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, SecretStoresResponse{SecretStores: []SecretStore{store}})
+	})
+	server.HandleFunc("/v1/secret-stores/global-default", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, store)
+	})
+	server.HandleFunc("/v1/secret-stores/preferred", func(w http.ResponseWriter, r *http.Request) {
+		writeFault(w, http.StatusNotFound, "Not Found", "No preferred secret store found.")
+	})
+	server.HandleFunc("/v1/secret-stores/hsm/preferred", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	stores, err := client.ListSecretStores(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list secret stores: %v", err)
+	}
+	if len(stores) != 1 || stores[0] != store {
+		t.Fatalf("Got %+v - want [%+v]", stores, store)
+	}
+	if global, err := client.GetGlobalDefaultSecretStore(ctx); err != nil || *global != store {
+		t.Fatalf("Got %v and error '%v' - want %+v", global, err, store)
+	}
+	if _, err = client.GetPreferredSecretStore(ctx); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+	if err = client.SetPreferredSecretStore(ctx, "hsm"); err != nil {
+		t.Fatalf("Failed to set preferred secret store: %v", err)
+	}
+	if err = client.UnsetPreferredSecretStore(ctx, store.SecretStoreRef); err != nil {
+		t.Fatalf("Failed to unset preferred secret store: %v", err)
+	}
+	if err = client.SetPreferredSecretStore(ctx, "missing"); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+}
+
+func TestSecretStoresDisabled(t *testing.T) {
+	server := newTestServer(t)
+	client := server.connect(t, nil) // No secret-stores resource without multiple backends
+
+	if _, err := client.ListSecretStores(context.Background()); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+}
@@ -2,52 +2,219 @@ package client
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
 )
 
 // Create stores the given key in Barbican if and only
-// if no entry with the given name existc.
+// if no entry with the given name exists.
 //
-// If no such entry exists, Create returns ErrKeyExistc.
-func (c *Client) Create(ctx context.Context, name string, value []byte) error {
-	const (
-		SecretType      = "opaque"
-		ContentType     = "application/octet-stream"
-		ContentEncoding = "base64"
-		Algorithm       = "aes"
-		BitLength       = 256
-		Mode            = "cbc"
+// The key is stored as opaque AES-256-CBC secret. Use
+// CreateWithOptions to store other kinds of secrets or
+// to choose another CreateMode.
+//
+// If such an entry exists, Create returns ErrKeyExists.
+func (c *Client) Create(ctx context.Context, name string, value []byte) (*BarbicanSecret, error) {
+	return c.CreateWithOptions(ctx, name, value,
+		WithAlgorithm("aes"),
+		WithBitLength(256),
+		WithMode("cbc"),
 	)
-	// Check if key already exists
-	if err := c.verifyKeyDoesNotExist(ctx, name); err != xerror.ErrKeyExists {
-		return err
+}
+
+// CreateWithOptions stores the given value in Barbican. The
+// secret metadata - secret type, algorithm, expiration, etc. -
+// is taken from opts.
+//
+// How existing secrets with the same name are handled depends
+// on the CreateMode. By default, the secret is only created if
+// no such secret exists. Otherwise, CreateWithOptions returns
+// ErrKeyExists. See CreateIfAbsent for the exact guarantees.
+//
+// The returned secret carries the secret reference assigned
+// by Barbican, so it can be addressed by ID right away. With
+// CreateOrReplace, the secret is returned together with an
+// error if not all replaced secrets could be deleted.
+func (c *Client) CreateWithOptions(ctx context.Context, name string, value []byte, opts ...SecretOption) (*BarbicanSecret, error) {
+	mode := NewSecretOptions(opts...).CreateMode
+	if mode == CreateIfAbsent {
+		// Fail fast in the common case. The actual
+		// check happens once the secret got created.
+		existing, err := c.secretsByName(ctx, name, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return nil, xerror.ErrKeyExists
+		}
 	}
 
-	// Create new key
-	request, err := json.Marshal(SecretCreateRequest{
-		SecretType:             SecretType,
-		Name:                   name,
-		Payload:                base64.StdEncoding.EncodeToString(value),
-		PayloadContentType:     ContentType,
-		PayloadContentEncoding: ContentEncoding,
-		Algorithm:              Algorithm,
-		BitLength:              BitLength,
-		Mode:                   Mode,
-	})
+	createRequest := NewSecretCreateRequest(name, value, opts...)
+	request, err := json.Marshal(createRequest)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	resp, err := c.client.HttpPost(ctx, c.apiEndpoint("secrets"), request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SecretRefResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return nil, fmt.Errorf("barbican: failed to create '%s': failed to parse server response: %v", name, err)
 	}
-	_, err = c.client.HttpPost(ctx, endpoint(c.config.Endpoint, "/v1/secrets"), request, nil)
-	return err
+	if response.SecretRef == "" {
+		return nil, fmt.Errorf("barbican: failed to create '%s': server response does not contain a secret reference", name)
+	}
+	secret := newCreatedSecret(createRequest, response.SecretRef)
+
+	switch mode {
+	case CreateIfAbsent:
+		secrets, err := c.secretsByName(ctx, name, 0)
+		if err != nil {
+			return nil, fmt.Errorf("barbican: failed to create '%s': failed to check for concurrently created secrets: %v", name, err)
+		}
+		if lostCreateRace(secrets, secret.ID()) {
+			// Another secret with this name may win. Remove ours again.
+			if err := c.DeleteSecretByID(ctx, secret.SecretRef); err != nil {
+				return nil, fmt.Errorf("barbican: failed to create '%s': failed to delete duplicate secret '%s': %v", name, secret.SecretRef, err)
+			}
+			return nil, xerror.ErrKeyExists
+		}
+	case CreateOrReplace:
+		secrets, err := c.secretsByName(ctx, name, 0)
+		if err != nil {
+			return secret, fmt.Errorf("barbican: failed to replace '%s': %v", name, err)
+		}
+		for _, s := range secrets {
+			if s.ID() == secret.ID() {
+				continue
+			}
+			if err := c.DeleteSecretByID(ctx, s.SecretRef); err != nil && !errors.Is(err, xerror.ErrKeyNotFound) {
+				return secret, fmt.Errorf("barbican: failed to replace '%s': failed to delete '%s': %v", name, s.SecretRef, err)
+			}
+		}
+	}
+	return secret, nil
+}
+
+// secretsByName returns up to limit secrets with the given
+// name, oldest first. If limit is 0, all secrets with the
+// given name are returned.
+func (c *Client) secretsByName(ctx context.Context, name string, limit int) ([]BarbicanSecret, error) {
+	const pageSize = 100
+	params := url.Values{
+		"name":   {name},
+		"sort":   {"created:asc"},
+		"limit":  {fmt.Sprint(pageSize)},
+		"offset": {"0"},
+	}
+	if limit > 0 && limit < pageSize {
+		params.Set("limit", fmt.Sprint(limit))
+	}
+
+	var secrets []BarbicanSecret
+	for {
+		resp, err := c.client.HttpGet(ctx, c.apiEndpoint("secrets"), params)
+		if err != nil {
+			return nil, err
+		}
+		var response BarbicanSecretsResponse
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return nil, fmt.Errorf("barbican: failed to fetch '%s': failed to parse key metadata: %v", name, err)
+		}
+		secrets = append(secrets, response.Secrets...)
+		if len(response.Secrets) == 0 || response.Next == "" || (limit > 0 && len(secrets) >= limit) {
+			break
+		}
+		params.Set("offset", fmt.Sprint(len(secrets)))
+	}
+
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return createdBefore(&secrets[i], &secrets[j])
+	})
+	if limit > 0 && len(secrets) > limit {
+		secrets = secrets[:limit]
+	}
+	return secrets, nil
 }
 
+// lostCreateRace reports whether the secret with the given ID
+// must yield to another secret with the same name, i.e. whether
+// any other secret was created before it. All concurrent creators
+// see the same secrets and hence agree on the one winner. If the
+// secret itself is not listed, it loses.
+func lostCreateRace(secrets []BarbicanSecret, id string) bool {
+	var own *BarbicanSecret
+	for i := range secrets {
+		if secrets[i].ID() == id {
+			own = &secrets[i]
+		}
+	}
+	if own == nil {
+		return true
+	}
+	for i := range secrets {
+		if secrets[i].ID() != id && createdBefore(&secrets[i], own) {
+			return true
+		}
+	}
+	return false
+}
+
+// createdBefore reports whether a has been created before b.
+// Creation timestamps only have a resolution of one second, so
+// secrets created within the same second are ordered by their
+// ID. Secrets without timestamp come first.
+func createdBefore(a, b *BarbicanSecret) bool {
+	if a.Created != b.Created {
+		return a.Created < b.Created
+	}
+	return a.ID() < b.ID()
+}
+
+// newCreatedSecret returns the metadata of a secret just
+// created by the given request under the given reference.
+func newCreatedSecret(request SecretCreateRequest, ref string) *BarbicanSecret {
+	secret := &BarbicanSecret{
+		ContentTypes: map[string]string{"default": request.PayloadContentType},
+		Name:         request.Name,
+		SecretRef:    ref,
+		SecretType:   request.SecretType,
+		Status:       "ACTIVE",
+	}
+	if request.Algorithm != "" {
+		secret.Algorithm = request.Algorithm
+	}
+	if request.BitLength != 0 {
+		secret.BitLength = request.BitLength
+	}
+	if request.Mode != "" {
+		secret.Mode = request.Mode
+	}
+	if request.Expiration != "" {
+		secret.Expiration = request.Expiration
+	}
+	return secret
+}
+
+// GetSecret returns the secret with the given name.
+//
+// Barbican allows multiple secrets with the same name. By
+// default, the first one is returned. If the connection uses
+// NameResolutionUnique, GetSecret returns ErrAmbiguousName
+// instead.
 func (c *Client) GetSecret(ctx context.Context, name string) (*BarbicanSecret, error) {
-	url := endpoint(c.config.Endpoint, "/v1/secrets") + "?name=" + name
-	resp, err := c.client.HttpGet(ctx, url, nil)
+	params := url.Values{"name": {name}}
+	if c.nameResolution == NameResolutionUnique {
+		params.Set("limit", "2")
+	}
+	resp, err := c.client.HttpGet(ctx, c.apiEndpoint("secrets"), params)
 	if err != nil {
 		return nil, err
 	}
@@ -61,10 +228,28 @@ func (c *Client) GetSecret(ctx context.Context, name string) (*BarbicanSecret, e
 	if len(response.Secrets) == 0 {
 		return nil, xerror.ErrKeyNotFound
 	}
+	if c.nameResolution == NameResolutionUnique && (len(response.Secrets) > 1 || response.Total > 1) {
+		return nil, xerror.ErrAmbiguousName
+	}
 
 	return &response.Secrets[0], nil
 }
 
+// GetSecretByID returns the secret addressed by the given
+// secret reference or UUID.
+func (c *Client) GetSecretByID(ctx context.Context, id string) (*BarbicanSecret, error) {
+	resp, err := c.client.HttpGet(ctx, c.resourceURL("secrets", id), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrKeyNotFound)
+	}
+
+	var secret BarbicanSecret
+	if err := json.Unmarshal(resp, &secret); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch '%s': failed to parse key metadata: %v", id, err)
+	}
+	return &secret, nil
+}
+
 func (c *Client) GetSecretWithPayload(ctx context.Context, name string) (*BarbicanSecretWithPayload, error) {
 	secret, err := c.GetSecret(ctx, name)
 	if err != nil {
@@ -72,8 +257,7 @@ func (c *Client) GetSecretWithPayload(ctx context.Context, name string) (*Barbic
 	}
 
 	// now we can get the secret payload
-	url := endpoint(secret.SecretRef, "/payload")
-	payload, err := c.client.HttpGet(ctx, url, nil)
+	payload, err := c.GetPayloadByID(ctx, secret.SecretRef)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +270,16 @@ func (c *Client) GetSecretWithPayload(ctx context.Context, name string) (*Barbic
 	return secPayload, nil
 }
 
+// GetPayloadByID returns the payload of the secret addressed
+// by the given secret reference or UUID.
+func (c *Client) GetPayloadByID(ctx context.Context, id string) ([]byte, error) {
+	payload, err := c.client.HttpGet(ctx, endpoint(c.resourceURL("secrets", id), "/payload"), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrKeyNotFound)
+	}
+	return payload, nil
+}
+
 // Delete deletes the key associated with the given name
 // from Barbican. It may not return an error if no
 // entry for the given name existc.
@@ -96,9 +290,14 @@ func (c *Client) DeleteSecret(ctx context.Context, name string) error {
 	}
 
 	// Now, we can delete the key using its UUID.
-	url := endpoint(secret.SecretRef)
-	_, err = c.client.HttpDelete(ctx, url, nil, nil)
-	return err
+	return c.DeleteSecretByID(ctx, secret.SecretRef)
+}
+
+// DeleteSecretByID deletes the secret addressed by the
+// given secret reference or UUID.
+func (c *Client) DeleteSecretByID(ctx context.Context, id string) error {
+	_, err := c.client.HttpDelete(ctx, c.resourceURL("secrets", id), nil, nil)
+	return notFound(err, xerror.ErrKeyNotFound)
 }
 
 // List returns a new Iterator over the Barbican.
@@ -118,7 +317,7 @@ func (c *Client) ListSecrets(ctx context.Context) (Iterator, error) {
 		var next string
 		const limit = 200 // We limit a listing page to 200. This an arbitrary but reasonable value.
 		for {
-			reqURL := endpoint(c.config.Endpoint, "/v1/secrets") + "?sort=name:asc&limit=" + fmt.Sprint(limit)
+			reqURL := c.apiEndpoint("secrets") + "?sort=name:asc&limit=" + fmt.Sprint(limit)
 			if next != "" {
 				reqURL = next
 			}
@@ -126,6 +325,7 @@ func (c *Client) ListSecrets(ctx context.Context) (Iterator, error) {
 			resp, err := c.client.HttpGet(ctx, reqURL, nil)
 			if err != nil {
 				cancel(fmt.Errorf("barbican: failed to list keys: %v", err))
+				break
 			}
 
 			var keys BarbicanSecretsResponse
@@ -156,11 +356,57 @@ func (c *Client) ListSecrets(ctx context.Context) (Iterator, error) {
 	}, nil
 }
 
-// Checks if a key already exists or not, if so returns ErrKeyExists
-func (c *Client) verifyKeyDoesNotExist(ctx context.Context, name string) error {
-	_, err := c.GetSecret(ctx, name)
-	if err != nil {
-		return xerror.ErrKeyExists
+// ListSecretsWithFilter returns a new SecretIterator over
+// the secrets matching the given filter, in the order given
+// by the filter's sort keys.
+//
+// Like ListSecrets, the returned iterator may or may not
+// reflect concurrent changes to the Barbican.
+func (c *Client) ListSecretsWithFilter(ctx context.Context, filter SecretFilter) (SecretIterator, error) {
+	params := filter.Query()
+	limit := filter.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
 	}
-	return nil
+	params.Set("limit", fmt.Sprint(limit))
+
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan BarbicanSecret, 10)
+
+	go func() {
+		defer close(values)
+
+		reqURL, reqParams := c.apiEndpoint("secrets"), params
+		for {
+			resp, err := c.client.HttpGet(ctx, reqURL, reqParams)
+			if err != nil {
+				cancel(fmt.Errorf("barbican: failed to list secrets: %v", err))
+				break
+			}
+
+			var secrets BarbicanSecretsResponse
+			if err := json.Unmarshal(resp, &secrets); err != nil {
+				cancel(fmt.Errorf("barbican: failed to list secrets: failed to parse server response: %v", err))
+				break
+			}
+			for _, secret := range secrets.Secrets {
+				select {
+				case values <- secret:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if secrets.Next == "" || len(secrets.Secrets) == 0 {
+				break
+			}
+			// The next link contains all query parameters.
+			reqURL, reqParams = secrets.Next, nil
+		}
+	}()
+	return &SecretListIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
 }
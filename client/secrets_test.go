@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestCreateReturnsSecret(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets", func(w http.ResponseWriter, r *http.Request) {
+		var request SecretCreateRequest
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil {
+			writeFault(w, http.StatusBadRequest, "Bad Request", "invalid request")
+			return
+		}
+		switch request.Name {
+		case "no-ref":
+			writeJSON(w, http.StatusCreated, map[string]string{})
+		default:
+			writeJSON(w, http.StatusCreated, SecretRefResponse{SecretRef: server.URL + "/v1/secrets/" + request.Name})
+		}
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	secret, err := client.CreateWithOptions(ctx, "my-key", []byte("Hello World"),
+		WithSecretType(SecretTypeSymmetric),
+		WithAlgorithm("aes"),
+		WithBitLength(256),
+		WithCreateMode(CreateAllowDuplicates),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	want := BarbicanSecret{
+		Algorithm:    "aes",
+		BitLength:    256,
+		ContentTypes: map[string]string{"default": ContentTypeOctetStream},
+		Name:         "my-key",
+		SecretRef:    server.URL + "/v1/secrets/my-key",
+		SecretType:   "symmetric",
+		Status:       "ACTIVE",
+	}
+	if !reflect.DeepEqual(*secret, want) {
+		t.Fatalf("Got %+v - want %+v", *secret, want)
+	}
+	if id := secret.ID(); id != "my-key" {
+		t.Fatalf("Got ID '%s' - want 'my-key'", id)
+	}
+
+	if _, err = client.CreateWithOptions(ctx, "no-ref", []byte("Hello World"), WithCreateMode(CreateAllowDuplicates)); err == nil {
+		t.Fatal("Created a secret without a secret reference")
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets", func(w http.ResponseWriter, r *http.Request) {
+		var secrets []BarbicanSecret
+		switch name := r.URL.Query().Get("name"); name {
+		case "unique":
+			secrets = []BarbicanSecret{{Name: name, SecretRef: server.URL + "/v1/secrets/unique-1"}}
+		case "duplicate":
+			secrets = []BarbicanSecret{
+				{Name: name, SecretRef: server.URL + "/v1/secrets/duplicate-1"},
+				{Name: name, SecretRef: server.URL + "/v1/secrets/duplicate-2"},
+			}
+		}
+		writeJSON(w, http.StatusOK, BarbicanSecretsResponse{Secrets: secrets, Total: len(secrets)})
+	})
+	server.HandleFunc("/v1/secrets/unique-1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, BarbicanSecret{Name: "unique", SecretRef: server.URL + "/v1/secrets/unique-1"})
+	})
+
+	ctx := context.Background()
+	for i, test := range getSecretTests {
+		client := server.connect(t, nil, WithNameResolution(test.Mode))
+		secret, err := client.GetSecret(ctx, test.Name)
+		if !errors.Is(err, test.Err) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, test.Err)
+		}
+		if err == nil && secret.ID() != test.ID {
+			t.Fatalf("Test %d: got secret '%s' - want '%s'", i, secret.ID(), test.ID)
+		}
+	}
+
+	client := server.connect(t, nil)
+	for i, ref := range []string{"unique-1", server.URL + "/v1/secrets/unique-1"} {
+		secret, err := client.GetSecretByID(ctx, ref)
+		if err != nil {
+			t.Fatalf("Test %d: failed to fetch '%s': %v", i, ref, err)
+		}
+		if secret.Name != "unique" {
+			t.Fatalf("Test %d: got secret '%s' - want 'unique'", i, secret.Name)
+		}
+	}
+	if _, err := client.GetSecretByID(ctx, "missing"); !errors.Is(err, xerror.ErrKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrKeyNotFound)
+	}
+}
+
+var getSecretTests = []struct {
+	Mode NameResolution
+	Name string
+	ID   string
+	Err  error
+}{
+	{Mode: NameResolutionFirst, Name: "unique", ID: "unique-1"},                   // 0
+	{Mode: NameResolutionFirst, Name: "duplicate", ID: "duplicate-1"},             // 1
+	{Mode: NameResolutionFirst, Name: "missing", Err: xerror.ErrKeyNotFound},      // 2
+	{Mode: NameResolutionUnique, Name: "unique", ID: "unique-1"},                  // 3
+	{Mode: NameResolutionUnique, Name: "duplicate", Err: xerror.ErrAmbiguousName}, // 4
+	{Mode: NameResolutionUnique, Name: "missing", Err: xerror.ErrKeyNotFound},     // 5
+}
+
+func TestLostCreateRace(t *testing.T) {
+	for i, test := range lostCreateRaceTests {
+		if lost := lostCreateRace(test.Secrets, test.ID); lost != test.Lost {
+			t.Fatalf("Test %d: got %v - want %v", i, lost, test.Lost)
+		}
+	}
+
+	// Exactly one of the secrets created within
+	// the same second must win.
+	secrets := []BarbicanSecret{
+		{SecretRef: "c", Created: "2023-01-01T00:00:00"},
+		{SecretRef: "a", Created: "2023-01-01T00:00:00"},
+		{SecretRef: "b", Created: "2023-01-01T00:00:00"},
+	}
+	var winners []string
+	for _, s := range secrets {
+		if !lostCreateRace(secrets, s.ID()) {
+			winners = append(winners, s.ID())
+		}
+	}
+	if len(winners) != 1 || winners[0] != "a" {
+		t.Fatalf("Got winners %v - want [a]", winners)
+	}
+}
+
+func TestCreateIfAbsentRace(t *testing.T) {
+	var deleted []string
+	server := newTestServer(t)
+	server.HandleFunc("/v1/secrets", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			writeJSON(w, http.StatusCreated, SecretRefResponse{SecretRef: server.URL + "/v1/secrets/b"})
+			return
+		}
+		var secrets []BarbicanSecret
+		if r.URL.Query().Get("limit") != "1" { // Only list the concurrent secret after creation
+			secrets = []BarbicanSecret{
+				{Name: "my-key", SecretRef: server.URL + "/v1/secrets/a", Created: "2023-01-01T00:00:00"},
+				{Name: "my-key", SecretRef: server.URL + "/v1/secrets/b", Created: "2023-01-01T00:00:00"},
+			}
+		}
+		writeJSON(w, http.StatusOK, BarbicanSecretsResponse{Secrets: secrets, Total: len(secrets)})
+	})
+	server.HandleFunc("/v1/secrets/b", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := server.connect(t, nil)
+
+	if _, err := client.Create(context.Background(), "my-key", []byte("Hello World")); !errors.Is(err, xerror.ErrKeyExists) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrKeyExists)
+	}
+	if len(deleted) != 1 || deleted[0] != http.MethodDelete {
+		t.Fatalf("Got requests %v for the losing secret - want [DELETE]", deleted)
+	}
+}
+
+var lostCreateRaceTests = []struct {
+	Secrets []BarbicanSecret
+	ID      string
+	Lost    bool
+}{
+	{ // 0 - No other secret
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}},
+		ID:      "a",
+		Lost:    false,
+	},
+	{ // 1 - Another secret created earlier
+		Secrets: []BarbicanSecret{{SecretRef: "b", Created: "2023-01-01T00:00:00"}, {SecretRef: "a", Created: "2023-01-01T00:00:01"}},
+		ID:      "a",
+		Lost:    true,
+	},
+	{ // 2 - Another secret created later
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}, {SecretRef: "b", Created: "2023-01-01T00:00:01"}},
+		ID:      "b",
+		Lost:    true,
+	},
+	{ // 3
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}, {SecretRef: "b", Created: "2023-01-01T00:00:01"}},
+		ID:      "a",
+		Lost:    false,
+	},
+	{ // 4 - Same second, the smaller ID wins
+		Secrets: []BarbicanSecret{{SecretRef: "b", Created: "2023-01-01T00:00:00"}, {SecretRef: "a", Created: "2023-01-01T00:00:00"}},
+		ID:      "a",
+		Lost:    false,
+	},
+	{ // 5
+		Secrets: []BarbicanSecret{{SecretRef: "b", Created: "2023-01-01T00:00:00"}, {SecretRef: "a", Created: "2023-01-01T00:00:00"}},
+		ID:      "b",
+		Lost:    true,
+	},
+	{ // 6 - Secrets without timestamp come first
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}, {SecretRef: "b"}},
+		ID:      "a",
+		Lost:    true,
+	},
+	{ // 7
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}, {SecretRef: "b"}},
+		ID:      "b",
+		Lost:    false,
+	},
+	{ // 8 - The own secret is not listed
+		Secrets: []BarbicanSecret{{SecretRef: "a", Created: "2023-01-01T00:00:00"}},
+		ID:      "b",
+		Lost:    true,
+	},
+	{Secrets: nil, ID: "a", Lost: true}, // 9
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// ListTransportKeys returns the transport keys of the secret
+// store plugin with the given name, or of all plugins if the
+// name is empty.
+func (c *Client) ListTransportKeys(ctx context.Context, pluginName string) ([]TransportKey, error) {
+	params := url.Values{"limit": {fmt.Sprint(DefaultPageSize)}}
+	if pluginName != "" {
+		params.Set("plugin_name", pluginName)
+	}
+	reqURL := c.apiEndpoint("transport_keys")
+
+	var keys []TransportKey
+	for {
+		resp, err := c.client.HttpGet(ctx, reqURL, params)
+		if err != nil {
+			return nil, err
+		}
+
+		var response TransportKeysResponse
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return nil, fmt.Errorf("barbican: failed to list transport keys: failed to parse server response: %v", err)
+		}
+		keys = append(keys, response.TransportKeys...)
+		if response.Next == "" || len(response.TransportKeys) == 0 {
+			return keys, nil
+		}
+		reqURL, params = response.Next, nil
+	}
+}
+
+// GetTransportKey returns the transport key addressed by
+// the given transport key reference or UUID.
+func (c *Client) GetTransportKey(ctx context.Context, ref string) (*TransportKey, error) {
+	resp, err := c.client.HttpGet(ctx, c.resourceURL("transport_keys", ref), nil)
+	if err != nil {
+		return nil, notFound(err, xerror.ErrTransportKeyNotFound)
+	}
+
+	var key TransportKey
+	if err := json.Unmarshal(resp, &key); err != nil {
+		return nil, fmt.Errorf("barbican: failed to fetch transport key '%s': failed to parse server response: %v", ref, err)
+	}
+	return &key, nil
+}
+
+// PublicKey returns the RSA public key of the transport key.
+// The transport key may either be a PEM-encoded certificate
+// or a PEM-encoded public key.
+func (k *TransportKey) PublicKey() (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(k.TransportKey))
+	if block == nil {
+		return nil, errors.New("barbican: transport key is not PEM encoded")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("barbican: invalid transport key certificate: %v", err)
+		}
+		key = cert.PublicKey
+	case "PUBLIC KEY":
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("barbican: invalid transport key: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("barbican: unsupported transport key type '%s'", block.Type)
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("barbican: transport key is not an RSA key")
+	}
+	return publicKey, nil
+}
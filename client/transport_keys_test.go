@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestTransportKeyPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "transport"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &rsaKey.PublicKey, rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	rsaPublicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	ecPublicKey, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	encode := func(blockType string, der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+	}
+
+	tests := []struct {
+		TransportKey string
+		ShouldFail   bool
+	}{
+		{TransportKey: encode("CERTIFICATE", cert)},                                                               // 0
+		{TransportKey: encode("PUBLIC KEY", rsaPublicKey)},                                                        // 1
+		{TransportKey: encode("PUBLIC KEY", ecPublicKey), ShouldFail: true},                                       // 2
+		{TransportKey: encode("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)), ShouldFail: true}, // 3
+		{TransportKey: encode("CERTIFICATE", []byte("not a certificate")), ShouldFail: true},                      // 4
+		{TransportKey: encode("PUBLIC KEY", []byte("not a public key")), ShouldFail: true},                        // 5
+		{TransportKey: "not PEM encoded", ShouldFail: true},                                                       // 6
+		{TransportKey: "", ShouldFail: true},                                                                      // 7
+	}
+	for i, test := range tests {
+		key := TransportKey{TransportKey: test.TransportKey}
+		publicKey, err := key.PublicKey()
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but parsed the transport key", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to parse transport key: %v", i, err)
+		}
+		if err == nil && !publicKey.Equal(&rsaKey.PublicKey) {
+			t.Fatalf("Test %d: got a different public key", i)
+		}
+	}
+}
+
+func TestListTransportKeys(t *testing.T) {
+	server := newTestServer(t)
+	server.HandleFunc("/v1/transport_keys", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("plugin_name") != "dogtag_crypto" {
+			writeJSON(w, http.StatusOK, TransportKeysResponse{TransportKeys: []TransportKey{}})
+			return
+		}
+		if r.URL.Query().Get("offset") == "" {
+			writeJSON(w, http.StatusOK, TransportKeysResponse{
+				Next:          server.URL + "/v1/transport_keys?plugin_name=dogtag_crypto&offset=1&limit=1",
+				TransportKeys: []TransportKey{{PluginName: "dogtag_crypto", TransportKeyRef: server.URL + "/v1/transport_keys/a"}},
+				Total:         2,
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, TransportKeysResponse{
+			TransportKeys: []TransportKey{{PluginName: "dogtag_crypto", TransportKeyRef: server.URL + "/v1/transport_keys/b"}},
+			Total:         2,
+		})
+	})
+	server.HandleFunc("/v1/transport_keys/a", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, TransportKey{PluginName: "dogtag_crypto", TransportKeyRef: server.URL + "/v1/transport_keys/a"})
+	})
+	client := server.connect(t, nil)
+	ctx := context.Background()
+
+	keys, err := client.ListTransportKeys(ctx, "dogtag_crypto")
+	if err != nil {
+		t.Fatalf("Failed to list transport keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID() != "a" || keys[1].ID() != "b" {
+		t.Fatalf("Got %+v - want the transport keys 'a' and 'b'", keys)
+	}
+	if keys, err = client.ListTransportKeys(ctx, "simple_crypto"); err != nil || len(keys) != 0 {
+		t.Fatalf("Got %+v and error '%v' - want no transport keys", keys, err)
+	}
+
+	key, err := client.GetTransportKey(ctx, "a")
+	if err != nil {
+		t.Fatalf("Failed to fetch transport key: %v", err)
+	}
+	if key.ID() != "a" {
+		t.Fatalf("Got transport key '%s' - want 'a'", key.ID())
+	}
+	if _, err = client.GetTransportKey(ctx, "missing"); !errors.Is(err, xerror.ErrTransportKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrTransportKeyNotFound)
+	}
+}
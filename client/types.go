@@ -2,21 +2,101 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/xhttp"
 )
 
 type Conn interface {
-	Create(ctx context.Context, name string, value []byte) error
+	Create(ctx context.Context, name string, value []byte) (*BarbicanSecret, error)
+	CreateWithOptions(ctx context.Context, name string, value []byte, opts ...SecretOption) (*BarbicanSecret, error)
 	GetSecret(ctx context.Context, name string) (*BarbicanSecret, error)
+	GetSecretByID(ctx context.Context, id string) (*BarbicanSecret, error)
 	GetSecretWithPayload(ctx context.Context, name string) (*BarbicanSecretWithPayload, error)
+	GetPayloadByID(ctx context.Context, id string) ([]byte, error)
 	DeleteSecret(ctx context.Context, name string) error
+	DeleteSecretByID(ctx context.Context, id string) error
 	ListSecrets(ctx context.Context) (Iterator, error)
+	ListSecretsWithFilter(ctx context.Context, filter SecretFilter) (SecretIterator, error)
+
+	GetSecretWithMetadata(ctx context.Context, id string) (*BarbicanSecret, error)
+	GetSecretMetadata(ctx context.Context, id string) (map[string]string, error)
+	PutSecretMetadata(ctx context.Context, id string, metadata map[string]string) error
+	AddSecretMetadatum(ctx context.Context, id, key, value string) error
+	UpdateSecretMetadatum(ctx context.Context, id, key, value string) error
+	DeleteSecretMetadatum(ctx context.Context, id, key string) error
+
+	GetSecretACL(ctx context.Context, id string) (*ACL, error)
+	SetSecretACL(ctx context.Context, id string, acl ACL) error
+	UpdateSecretACL(ctx context.Context, id string, update ACLUpdate) error
+	DeleteSecretACL(ctx context.Context, id string) error
+
+	RegisterSecretConsumer(ctx context.Context, id string, consumer SecretConsumer) error
+	ListSecretConsumers(ctx context.Context, id string) ([]SecretConsumer, error)
+	RemoveSecretConsumer(ctx context.Context, id string, consumer SecretConsumer) error
+
+	CreateContainer(ctx context.Context, spec ContainerSpec) (*BarbicanContainer, error)
+	GetContainer(ctx context.Context, ref string) (*BarbicanContainer, error)
+	DeleteContainer(ctx context.Context, ref string) error
+	AddContainerSecret(ctx context.Context, ref string, secret ContainerSecretRef) error
+	RemoveContainerSecret(ctx context.Context, ref string, secret ContainerSecretRef) error
+	ListContainers(ctx context.Context) (ContainerIterator, error)
+
+	GetContainerACL(ctx context.Context, ref string) (*ACL, error)
+	SetContainerACL(ctx context.Context, ref string, acl ACL) error
+	UpdateContainerACL(ctx context.Context, ref string, update ACLUpdate) error
+	DeleteContainerACL(ctx context.Context, ref string) error
+
+	RegisterContainerConsumer(ctx context.Context, ref string, consumer ContainerConsumer) error
+	ListContainerConsumers(ctx context.Context, ref string) ([]ContainerConsumer, error)
+	RemoveContainerConsumer(ctx context.Context, ref string, consumer ContainerConsumer) error
+
+	SubmitOrder(ctx context.Context, spec OrderSpec) (*BarbicanOrder, error)
+	GetOrder(ctx context.Context, ref string) (*BarbicanOrder, error)
+	WaitForOrder(ctx context.Context, ref string, interval time.Duration) (*BarbicanOrder, error)
+	DeleteOrder(ctx context.Context, ref string) error
+	ListOrders(ctx context.Context) (OrderIterator, error)
+
+	ListSecretStores(ctx context.Context) ([]SecretStore, error)
+	GetSecretStore(ctx context.Context, ref string) (*SecretStore, error)
+	GetGlobalDefaultSecretStore(ctx context.Context) (*SecretStore, error)
+	GetPreferredSecretStore(ctx context.Context) (*SecretStore, error)
+	SetPreferredSecretStore(ctx context.Context, ref string) error
+	UnsetPreferredSecretStore(ctx context.Context, ref string) error
+
+	GetQuotas(ctx context.Context) (*Quotas, error)
+	ListProjectQuotas(ctx context.Context) ([]ProjectQuotasEntry, error)
+	GetProjectQuotas(ctx context.Context, projectID string) (*ProjectQuotas, error)
+	SetProjectQuotas(ctx context.Context, projectID string, quotas ProjectQuotas) error
+	DeleteProjectQuotas(ctx context.Context, projectID string) error
+
+	ListTransportKeys(ctx context.Context, pluginName string) ([]TransportKey, error)
+	GetTransportKey(ctx context.Context, ref string) (*TransportKey, error)
+
+	ListCAs(ctx context.Context, pluginName string) ([]CA, error)
+	GetCA(ctx context.Context, ref string) (*CA, error)
+	GetCACertificate(ctx context.Context, ref string) ([]byte, error)
+	GetCAIntermediates(ctx context.Context, ref string) ([]byte, error)
+	GetPreferredCA(ctx context.Context) (*CA, error)
+	SetPreferredCA(ctx context.Context, ref string) error
+	AddCAToProject(ctx context.Context, ref string) error
+	RemoveCAFromProject(ctx context.Context, ref string) error
+	GetGlobalPreferredCA(ctx context.Context) (*CA, error)
+	SetGlobalPreferredCA(ctx context.Context, ref string) error
+	UnsetGlobalPreferredCA(ctx context.Context) error
+
+	APIVersion() APIVersion
+	Close(ctx context.Context) error
 }
 
 type Client struct {
 	client *xhttp.Client
 	config *xhttp.Config
+
+	version APIVersion
+	baseURL string // The endpoint of the negotiated major version, e.g. https://barbican:9311/v1.
+
+	nameResolution NameResolution
 }
 
 type SecretCreateRequest struct {
@@ -31,6 +111,10 @@ type SecretCreateRequest struct {
 	SecretType             string `json:"secret_type,omitempty"`              // (optional) Used to indicate the type of secret being stored. For more information see Secret Types (default: opaque)
 }
 
+type SecretRefResponse struct {
+	SecretRef string `json:"secret_ref"`
+}
+
 type BarbicanSecret struct {
 	Algorithm    interface{}       `json:"algorithm"`
 	BitLength    interface{}       `json:"bit_length"`
@@ -38,6 +122,7 @@ type BarbicanSecret struct {
 	Created      string            `json:"created"`
 	CreatorID    string            `json:"creator_id"`
 	Expiration   interface{}       `json:"expiration"`
+	Metadata     map[string]string `json:"metadata,omitempty"` // Only set if requested, e.g. by GetSecretWithMetadata.
 	Mode         interface{}       `json:"mode"`
 	Name         string            `json:"name"`
 	SecretRef    string            `json:"secret_ref"`
@@ -46,6 +131,18 @@ type BarbicanSecret struct {
 	Updated      string            `json:"updated"`
 }
 
+// ID returns the UUID of the secret.
+func (s *BarbicanSecret) ID() string { return RefID(s.SecretRef) }
+
+type SecretMetadata struct {
+	Metadata map[string]string `json:"metadata"`
+}
+
+type SecretMetadatum struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 type BarbicanSecretWithPayload struct {
 	Payload []byte         `json:"payload"`
 	Secret  BarbicanSecret `json:"secret"`
@@ -57,3 +154,419 @@ type BarbicanSecretsResponse struct {
 	Secrets  []BarbicanSecret `json:"secrets"`
 	Total    int              `json:"total"`
 }
+
+// ContainerType is the type of a Barbican container.
+type ContainerType string
+
+const (
+	ContainerTypeGeneric     ContainerType = "generic"
+	ContainerTypeRSA         ContainerType = "rsa"
+	ContainerTypeCertificate ContainerType = "certificate"
+)
+
+// Well-known secret reference names of rsa and certificate containers.
+const (
+	ContainerSecretPublicKey            = "public_key"
+	ContainerSecretPrivateKey           = "private_key"
+	ContainerSecretPrivateKeyPassphrase = "private_key_passphrase"
+	ContainerSecretCertificate          = "certificate"
+	ContainerSecretIntermediates        = "intermediates"
+)
+
+type ContainerSecretRef struct {
+	Name      string `json:"name,omitempty"` // (optional) The name of the secret within the container.
+	SecretRef string `json:"secret_ref"`     // The full URI reference to the secret.
+}
+
+type ContainerCreateRequest struct {
+	Name       string               `json:"name,omitempty"`        // (optional) The name of the container set by the user.
+	Type       ContainerType        `json:"type"`                  // The type of the container: generic, rsa or certificate.
+	SecretRefs []ContainerSecretRef `json:"secret_refs,omitempty"` // (optional) The secrets referenced by the container.
+}
+
+type ContainerRefResponse struct {
+	ContainerRef string `json:"container_ref"`
+}
+
+type ContainerConsumer struct {
+	Name    string `json:"name"`              // The name of the consuming service, e.g. octavia.
+	URL     string `json:"URL"`               // The URL of the consuming resource, e.g. a load balancer.
+	Created string `json:"created,omitempty"` // Only set in listings.
+	Updated string `json:"updated,omitempty"` // Only set in listings.
+	Status  string `json:"status,omitempty"`  // Only set in listings.
+}
+
+type ContainerConsumersResponse struct {
+	Next      string              `json:"next"`
+	Previous  string              `json:"previous"`
+	Consumers []ContainerConsumer `json:"consumers"`
+	Total     int                 `json:"total"`
+}
+
+type SecretConsumer struct {
+	Service      string `json:"service"`           // The name of the consuming service, e.g. image.
+	ResourceType string `json:"resource_type"`     // The type of the consuming resource, e.g. image.
+	ResourceID   string `json:"resource_id"`       // The UUID of the consuming resource.
+	Created      string `json:"created,omitempty"` // Only set in listings.
+	Updated      string `json:"updated,omitempty"` // Only set in listings.
+	Status       string `json:"status,omitempty"`  // Only set in listings.
+}
+
+type SecretConsumersResponse struct {
+	Next      string           `json:"next"`
+	Previous  string           `json:"previous"`
+	Consumers []SecretConsumer `json:"consumers"`
+	Total     int              `json:"total"`
+}
+
+type BarbicanContainer struct {
+	Consumers    []ContainerConsumer  `json:"consumers"`
+	ContainerRef string               `json:"container_ref"`
+	Created      string               `json:"created"`
+	CreatorID    string               `json:"creator_id"`
+	Name         string               `json:"name"`
+	SecretRefs   []ContainerSecretRef `json:"secret_refs"`
+	Status       string               `json:"status"`
+	Type         ContainerType        `json:"type"`
+	Updated      string               `json:"updated"`
+}
+
+type BarbicanContainersResponse struct {
+	Next       string              `json:"next"`
+	Previous   string              `json:"previous"`
+	Containers []BarbicanContainer `json:"containers"`
+	Total      int                 `json:"total"`
+}
+
+// ContainerSpec describes a container to be created.
+// It is implemented by GenericContainer, RSAContainer
+// and CertificateContainer.
+type ContainerSpec interface {
+	CreateRequest() ContainerCreateRequest
+}
+
+// GenericContainer is a container holding an arbitrary
+// set of named secret references.
+type GenericContainer struct {
+	Name       string
+	SecretRefs []ContainerSecretRef
+}
+
+// RSAContainer is a container holding an RSA key pair.
+type RSAContainer struct {
+	Name                    string
+	PublicKeyRef            string
+	PrivateKeyRef           string
+	PrivateKeyPassphraseRef string // (optional)
+}
+
+// CertificateContainer is a container holding a TLS
+// certificate and, optionally, its private key and
+// intermediate certificates.
+type CertificateContainer struct {
+	Name                    string
+	CertificateRef          string
+	PrivateKeyRef           string // (optional)
+	PrivateKeyPassphraseRef string // (optional)
+	IntermediatesRef        string // (optional)
+}
+
+// OrderType is the type of a Barbican order.
+type OrderType string
+
+const (
+	OrderTypeKey         OrderType = "key"
+	OrderTypeAsymmetric  OrderType = "asymmetric"
+	OrderTypeCertificate OrderType = "certificate"
+)
+
+// Order statuses. An order starts PENDING and ends up
+// either ACTIVE or ERROR.
+const (
+	OrderStatusPending = "PENDING"
+	OrderStatusActive  = "ACTIVE"
+	OrderStatusError   = "ERROR"
+)
+
+// Certificate order request types.
+const (
+	CertificateRequestSimpleCMC = "simple-cmc"
+	CertificateRequestFullCMC   = "full-cmc"
+	CertificateRequestStoredKey = "stored-key"
+	CertificateRequestCustom    = "custom"
+)
+
+type OrderMeta struct {
+	Name               string `json:"name,omitempty"`                 // (optional) The name of the generated secret or container.
+	Algorithm          string `json:"algorithm,omitempty"`            // (key, asymmetric) The algorithm of the generated key, e.g. aes or rsa.
+	BitLength          int    `json:"bit_length,omitempty"`           // (key, asymmetric) The bit length of the generated key.
+	Mode               string `json:"mode,omitempty"`                 // (optional) (key) The mode of the generated key, e.g. cbc.
+	PassPhrase         string `json:"pass_phrase,omitempty"`          // (optional) (asymmetric) The passphrase protecting the generated private key.
+	PayloadContentType string `json:"payload_content_type,omitempty"` // (optional) The media type of the generated secret payload.
+	Expiration         string `json:"expiration,omitempty"`           // (optional) UTC timestamp in ISO 8601 format after which the generated secret expires.
+	RequestType        string `json:"request_type,omitempty"`         // (certificate) simple-cmc, full-cmc, stored-key or custom.
+	SubjectDN          string `json:"subject_dn,omitempty"`           // (certificate) The subject of the certificate, e.g. CN=example.com.
+	SourceContainerRef string `json:"container_ref,omitempty"`        // (certificate) (stored-key) The rsa container holding the key to certify.
+	CAID               string `json:"ca_id,omitempty"`                // (optional) (certificate) The UUID of the CA that should issue the certificate.
+	Profile            string `json:"profile,omitempty"`              // (optional) (certificate) The CA profile to use.
+	RequestData        string `json:"request_data,omitempty"`         // (certificate) (simple-cmc) The base64 encoded PKCS#10 certificate request.
+}
+
+type OrderCreateRequest struct {
+	Type OrderType `json:"type"` // The type of the order: key, asymmetric or certificate.
+	Meta OrderMeta `json:"meta"` // The parameters of the secret or container to generate.
+}
+
+type OrderRefResponse struct {
+	OrderRef string `json:"order_ref"`
+}
+
+type BarbicanOrder struct {
+	ContainerRef     string    `json:"container_ref,omitempty"`
+	Created          string    `json:"created"`
+	CreatorID        string    `json:"creator_id"`
+	ErrorReason      string    `json:"error_reason,omitempty"`
+	ErrorStatusCode  string    `json:"error_status_code,omitempty"`
+	Meta             OrderMeta `json:"meta"`
+	OrderRef         string    `json:"order_ref"`
+	SecretRef        string    `json:"secret_ref,omitempty"`
+	Status           string    `json:"status"`
+	SubStatus        string    `json:"sub_status,omitempty"`
+	SubStatusMessage string    `json:"sub_status_message,omitempty"`
+	Type             OrderType `json:"type"`
+	Updated          string    `json:"updated"`
+}
+
+type BarbicanOrdersResponse struct {
+	Next     string          `json:"next"`
+	Previous string          `json:"previous"`
+	Orders   []BarbicanOrder `json:"orders"`
+	Total    int             `json:"total"`
+}
+
+// OrderSpec describes an order to be submitted.
+// It is implemented by KeyOrder, AsymmetricOrder
+// and CertificateOrder.
+type OrderSpec interface {
+	CreateRequest() OrderCreateRequest
+}
+
+// KeyOrder requests the generation of a symmetric key.
+// The resulting order references a secret.
+type KeyOrder struct {
+	Name               string
+	Algorithm          string // e.g. aes
+	BitLength          int    // e.g. 256
+	Mode               string // (optional) e.g. cbc
+	PayloadContentType string // (optional) defaults to application/octet-stream
+	Expiration         time.Time
+}
+
+// AsymmetricOrder requests the generation of a key pair.
+// The resulting order references an rsa container.
+type AsymmetricOrder struct {
+	Name               string
+	Algorithm          string // e.g. rsa
+	BitLength          int    // e.g. 2048
+	PassPhrase         string // (optional)
+	PayloadContentType string // (optional) defaults to application/octet-stream
+	Expiration         time.Time
+}
+
+// CertificateOrder requests the issuance of a certificate.
+// The resulting order references a certificate container.
+type CertificateOrder struct {
+	Name               string
+	RequestType        string // e.g. CertificateRequestStoredKey
+	SubjectDN          string // (stored-key) e.g. CN=example.com
+	SourceContainerRef string // (stored-key) rsa container holding the key pair
+	CAID               string // (optional) the UUID or reference of the CA that should issue the certificate
+	Profile            string // (optional)
+	RequestData        string // (simple-cmc) base64 encoded PKCS#10 request
+}
+
+// SecretStore is a secret store backend of a Barbican
+// deployment with multiple backends enabled, e.g. an HSM.
+type SecretStore struct {
+	Created           string `json:"created"`
+	CryptoPlugin      string `json:"crypto_plugin,omitempty"`
+	GlobalDefault     bool   `json:"global_default"`
+	Name              string `json:"name"`
+	SecretStorePlugin string `json:"secret_store_plugin"`
+	SecretStoreRef    string `json:"secret_store_ref"`
+	Status            string `json:"status"`
+	Updated           string `json:"updated"`
+}
+
+// ID returns the UUID of the secret store.
+func (s *SecretStore) ID() string { return RefID(s.SecretStoreRef) }
+
+type SecretStoresResponse struct {
+	SecretStores []SecretStore `json:"secret_stores"`
+	Total        int           `json:"total"`
+}
+
+// UnlimitedQuota is the quota value that imposes no limit.
+// A quota of 0 disables the creation of the resource.
+const UnlimitedQuota = -1
+
+// Quotas are the effective quotas of a project - i.e. the
+// maximum number of resources of each kind the project may
+// own. UnlimitedQuota means there is no limit.
+type Quotas struct {
+	Secrets    int `json:"secrets"`
+	Orders     int `json:"orders"`
+	Containers int `json:"containers"`
+	Consumers  int `json:"consumers"`
+	CAs        int `json:"cas"`
+}
+
+type QuotasResponse struct {
+	Quotas Quotas `json:"quotas"`
+}
+
+// ProjectQuotas are quotas configured for a specific project.
+// Nil values fall back to the deployment's default quotas.
+type ProjectQuotas struct {
+	Secrets    *int `json:"secrets,omitempty"`
+	Orders     *int `json:"orders,omitempty"`
+	Containers *int `json:"containers,omitempty"`
+	Consumers  *int `json:"consumers,omitempty"`
+	CAs        *int `json:"cas,omitempty"`
+}
+
+// Apply returns the given default quotas overridden
+// by the quotas configured for the project.
+func (q ProjectQuotas) Apply(defaults Quotas) Quotas {
+	override := func(v *int, def int) int {
+		if v != nil {
+			return *v
+		}
+		return def
+	}
+	return Quotas{
+		Secrets:    override(q.Secrets, defaults.Secrets),
+		Orders:     override(q.Orders, defaults.Orders),
+		Containers: override(q.Containers, defaults.Containers),
+		Consumers:  override(q.Consumers, defaults.Consumers),
+		CAs:        override(q.CAs, defaults.CAs),
+	}
+}
+
+type ProjectQuotasRequest struct {
+	ProjectQuotas ProjectQuotas `json:"project_quotas"`
+}
+
+type ProjectQuotasResponse struct {
+	ProjectQuotas ProjectQuotas `json:"project_quotas"`
+}
+
+// ProjectQuotasEntry are the quotas configured
+// for the project with the given ID.
+type ProjectQuotasEntry struct {
+	ProjectID     string        `json:"project_id"`
+	ProjectQuotas ProjectQuotas `json:"project_quotas"`
+}
+
+type ProjectQuotasListResponse struct {
+	Next          string               `json:"next"`
+	Previous      string               `json:"previous"`
+	ProjectQuotas []ProjectQuotasEntry `json:"project_quotas"`
+	Total         int                  `json:"total"`
+}
+
+// TransportKey is a key of a secret store plugin used to
+// protect payloads in transit, in addition to TLS.
+//
+// The client only exposes transport keys; it does not wrap
+// payloads with them. Barbican expects wrapped payloads as a
+// Dogtag PKIArchiveOptions (CRMF) structure together with a
+// trans_wrapped_session_key, which is specific to the Dogtag
+// plugin and not implemented here.
+type TransportKey struct {
+	Created         string `json:"created"`
+	PluginName      string `json:"plugin_name"`
+	TransportKey    string `json:"transport_key"` // The PEM-encoded public key or certificate.
+	TransportKeyRef string `json:"transport_key_ref"`
+	Updated         string `json:"updated"`
+}
+
+// ID returns the UUID of the transport key.
+func (k *TransportKey) ID() string { return RefID(k.TransportKeyRef) }
+
+type TransportKeysResponse struct {
+	Next          string         `json:"next"`
+	Previous      string         `json:"previous"`
+	TransportKeys []TransportKey `json:"transport_keys"`
+	Total         int            `json:"total"`
+}
+
+// CA is a certificate authority issuing the certificates
+// of certificate orders.
+type CA struct {
+	CAID       string              `json:"ca_id"`
+	Created    string              `json:"created"`
+	CreatorID  string              `json:"creator_id,omitempty"`
+	Expiration string              `json:"expiration"`
+	Meta       []map[string]string `json:"meta"` // Each entry holds a single attribute, e.g. {"name": "Dogtag CA"}.
+	ParentCAID string              `json:"parent_ca_id,omitempty"`
+	PluginCAID string              `json:"plugin_ca_id"`
+	PluginName string              `json:"plugin_name"`
+	ProjectID  string              `json:"project_id,omitempty"`
+	Status     string              `json:"status"`
+	Updated    string              `json:"updated"`
+}
+
+// ID returns the UUID of the CA.
+func (ca *CA) ID() string { return ca.CAID }
+
+// Name returns the name of the CA.
+func (ca *CA) Name() string { return ca.meta("name") }
+
+// Description returns the description of the CA.
+func (ca *CA) Description() string { return ca.meta("description") }
+
+// SigningCertificate returns the PEM encoded
+// signing certificate of the CA, if present.
+func (ca *CA) SigningCertificate() string { return ca.meta("ca_signing_certificate") }
+
+// Intermediates returns the PEM encoded intermediate
+// certificates of the CA, if present.
+func (ca *CA) Intermediates() string { return ca.meta("intermediates") }
+
+func (ca *CA) meta(key string) string {
+	for _, m := range ca.Meta {
+		if v, ok := m[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+type CARefResponse struct {
+	CARef string `json:"ca_ref"`
+}
+
+type CAsResponse struct {
+	CAs      []string `json:"cas"` // The CA references.
+	Next     string   `json:"next"`
+	Previous string   `json:"previous"`
+	Total    int      `json:"total"`
+}
+
+// VersionDocument describes a major version of the Barbican
+// API and the range of microversions supported by the server.
+type VersionDocument struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	MinVersion string `json:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty"`
+}
+
+// VersionsResponse is the version document
+// of the Barbican root endpoint.
+type VersionsResponse struct {
+	Versions struct {
+		Values []VersionDocument `json:"values"`
+	} `json:"versions"`
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// DefaultAPIVersion is the major version of the Barbican
+// API used if the server does not publish a version document.
+const DefaultAPIVersion = "v1"
+
+var (
+	// MaxMicroversion is the highest key-manager API
+	// microversion supported by the SDK.
+	MaxMicroversion = Microversion{Major: 1, Minor: 1}
+
+	// secretConsumersMicroversion is the microversion
+	// introducing secret consumers.
+	secretConsumersMicroversion = Microversion{Major: 1, Minor: 1}
+)
+
+// Microversion is a key-manager API microversion, e.g. 1.1.
+type Microversion struct {
+	Major int
+	Minor int
+}
+
+// ParseMicroversion parses a microversion like "1.1".
+func ParseMicroversion(s string) (Microversion, error) {
+	major, minor, ok := strings.Cut(strings.TrimSpace(s), ".")
+	if !ok {
+		return Microversion{}, fmt.Errorf("invalid microversion '%s'", s)
+	}
+	x, err := strconv.Atoi(major)
+	if err != nil || x < 0 {
+		return Microversion{}, fmt.Errorf("invalid microversion '%s'", s)
+	}
+	y, err := strconv.Atoi(minor)
+	if err != nil || y < 0 {
+		return Microversion{}, fmt.Errorf("invalid microversion '%s'", s)
+	}
+	return Microversion{Major: x, Minor: y}, nil
+}
+
+// Less reports whether v is older than other.
+func (v Microversion) Less(other Microversion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	return v.Minor < other.Minor
+}
+
+func (v Microversion) String() string { return fmt.Sprintf("%d.%d", v.Major, v.Minor) }
+
+// Features returns the optional capabilities of
+// the Barbican API available at microversion v.
+func (v Microversion) Features() Features {
+	return Features{
+		SecretConsumers: !v.Less(secretConsumersMicroversion),
+	}
+}
+
+// Features are optional capabilities of the Barbican API
+// that depend on the negotiated microversion. Methods using
+// an unavailable capability fail with xerror.ErrNotSupported.
+type Features struct {
+	// SecretConsumers reports whether secret consumers
+	// can be registered, listed and removed.
+	SecretConsumers bool
+}
+
+// APIVersion describes the version of the Barbican API
+// used by a connection.
+type APIVersion struct {
+	ID     string // The major version, e.g. "v1".
+	Status string // The status published by the server, if any, e.g. "stable".
+
+	// MinMicroversion and MaxMicroversion are the microversions
+	// supported by the server. Both are 1.0 if the server does
+	// not support microversions.
+	MinMicroversion Microversion
+	MaxMicroversion Microversion
+
+	// Microversion is the negotiated microversion
+	// requested by the connection.
+	Microversion Microversion
+
+	// Features are the capabilities available
+	// at the negotiated microversion.
+	Features Features
+}
+
+// APIVersion returns the Barbican API version negotiated
+// when the connection has been established.
+func (c *Client) APIVersion() APIVersion {
+	return c.version
+}
+
+// discoverVersion fetches the version document of the Barbican
+// root endpoint and negotiates the microversion. If the server
+// does not publish a version document (404 Not Found), API v1
+// without microversions is assumed.
+func (c *Client) discoverVersion(ctx context.Context) error {
+	root := rootEndpoint(c.config.Endpoint)
+	version := APIVersion{
+		ID:              DefaultAPIVersion,
+		MinMicroversion: Microversion{Major: 1},
+		MaxMicroversion: Microversion{Major: 1},
+	}
+	var microversions bool
+
+	resp, err := c.client.HttpGet(ctx, root, nil)
+	var e xerror.Error
+	switch {
+	case errors.As(err, &e) && e.Status() == http.StatusNotFound:
+		// No version document. Fall back to the defaults.
+	case err != nil:
+		return err
+	default:
+		var response VersionsResponse
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return fmt.Errorf("failed to parse version document: %v", err)
+		}
+		doc, ok := response.find(DefaultAPIVersion)
+		if !ok {
+			return fmt.Errorf("server does not support API %s", DefaultAPIVersion)
+		}
+		version.ID, version.Status = doc.ID, doc.Status
+		if doc.MaxVersion != "" {
+			if version.MaxMicroversion, err = ParseMicroversion(doc.MaxVersion); err != nil {
+				return err
+			}
+			if doc.MinVersion != "" {
+				if version.MinMicroversion, err = ParseMicroversion(doc.MinVersion); err != nil {
+					return err
+				}
+			}
+			microversions = true
+		}
+	}
+
+	if version.Microversion, err = negotiateMicroversion(version.MinMicroversion, version.MaxMicroversion, c.config.Microversion); err != nil {
+		return err
+	}
+	version.Features = version.Microversion.Features()
+	if microversions {
+		c.client.SetMicroversion(version.Microversion.String())
+	}
+	c.version = version
+	c.baseURL = endpoint(root, DefaultAPIVersion)
+	return nil
+}
+
+// negotiateMicroversion returns the pinned microversion, if
+// not empty, or the highest microversion supported by both the
+// server and the SDK.
+func negotiateMicroversion(low, high Microversion, pinned string) (Microversion, error) {
+	if pinned != "" {
+		v, err := ParseMicroversion(pinned)
+		if err != nil {
+			return Microversion{}, err
+		}
+		if MaxMicroversion.Less(v) {
+			return Microversion{}, fmt.Errorf("microversion %s is not supported by the SDK (maximum: %s)", v, MaxMicroversion)
+		}
+		if v.Less(low) || high.Less(v) {
+			return Microversion{}, fmt.Errorf("microversion %s is not supported by the server (supported: %s - %s)", v, low, high)
+		}
+		return v, nil
+	}
+
+	v := high
+	if MaxMicroversion.Less(v) {
+		v = MaxMicroversion
+	}
+	if v.Less(low) {
+		return Microversion{}, fmt.Errorf("server requires microversion %s or newer but the SDK supports up to %s", low, MaxMicroversion)
+	}
+	return v, nil
+}
+
+// rootEndpoint returns the Barbican endpoint without a
+// trailing major version, e.g. "https://barbican:9311"
+// for "https://barbican:9311/v1/".
+func rootEndpoint(endpoint string) string {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	return strings.TrimSuffix(endpoint, "/"+DefaultAPIVersion)
+}
+
+// find returns the version document of the given major version.
+func (r *VersionsResponse) find(id string) (VersionDocument, bool) {
+	for _, doc := range r.Versions.Values {
+		if strings.EqualFold(doc.ID, id) {
+			return doc, true
+		}
+	}
+	return VersionDocument{}, false
+}
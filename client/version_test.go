@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xhttp"
+)
+
+func TestParseMicroversion(t *testing.T) {
+	for i, test := range parseMicroversionTests {
+		v, err := ParseMicroversion(test.String)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but parsed '%s'", i, v)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to parse microversion: %v", i, err)
+		}
+		if err == nil && v != test.Version {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, v, test.Version)
+		}
+	}
+}
+
+func TestNegotiateMicroversion(t *testing.T) {
+	for i, test := range negotiateMicroversionTests {
+		v, err := negotiateMicroversion(test.Low, test.High, test.Pinned)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but negotiated '%s'", i, v)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to negotiate microversion: %v", i, err)
+		}
+		if err == nil && v != test.Version {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, v, test.Version)
+		}
+	}
+}
+
+func TestDiscoverVersion(t *testing.T) {
+	for i, test := range discoverVersionTests {
+		server := newTestServer(t)
+		server.root = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(test.Status)
+			w.Write([]byte(test.Versions))
+		}
+		var header string
+		server.HandleFunc("/v1/secrets/my-key", func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get(xhttp.APIVersionHeader)
+			writeJSON(w, http.StatusOK, BarbicanSecret{SecretRef: server.URL + "/v1/secrets/my-key"})
+		})
+
+		client, err := New(context.Background(), server.config())
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but connected", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to connect: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		defer client.Close(context.Background())
+
+		if version := client.APIVersion(); version != test.Version {
+			t.Fatalf("Test %d: got version %+v - want %+v", i, version, test.Version)
+		}
+		if _, err = client.GetSecretByID(context.Background(), "my-key"); err != nil {
+			t.Fatalf("Test %d: failed to fetch secret: %v", i, err)
+		}
+		if header != test.Header {
+			t.Fatalf("Test %d: got microversion header '%s' - want '%s'", i, header, test.Header)
+		}
+	}
+}
+
+var parseMicroversionTests = []struct {
+	String     string
+	Version    Microversion
+	ShouldFail bool
+}{
+	{String: "1.0", Version: Microversion{Major: 1}},             // 0
+	{String: " 1.1 ", Version: Microversion{Major: 1, Minor: 1}}, // 1
+	{String: "2.10", Version: Microversion{Major: 2, Minor: 10}}, // 2
+	{String: "1", ShouldFail: true},                              // 3
+	{String: "", ShouldFail: true},                               // 4
+	{String: "1.x", ShouldFail: true},                            // 5
+	{String: "-1.0", ShouldFail: true},                           // 6
+	{String: "1.-1", ShouldFail: true},                           // 7
+	{String: "1.1.1", ShouldFail: true},                          // 8
+}
+
+var negotiateMicroversionTests = []struct {
+	Low, High  Microversion
+	Pinned     string
+	Version    Microversion
+	ShouldFail bool
+}{
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1}, Version: Microversion{Major: 1}},                                    // 0
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 1}, Version: Microversion{Major: 1, Minor: 1}},                // 1
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 9}, Version: MaxMicroversion},                                 // 2 - Capped by the SDK
+	{Low: Microversion{Major: 1, Minor: 5}, High: Microversion{Major: 1, Minor: 9}, ShouldFail: true},                               // 3 - Server too new
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 1}, Pinned: "1.0", Version: Microversion{Major: 1}},           // 4
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 1}, Pinned: "1.1", Version: Microversion{Major: 1, Minor: 1}}, // 5
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 9}, Pinned: "1.5", ShouldFail: true},                          // 6 - Not supported by the SDK
+	{Low: Microversion{Major: 1, Minor: 1}, High: Microversion{Major: 1, Minor: 1}, Pinned: "1.0", ShouldFail: true},                // 7 - Not supported by the server
+	{Low: Microversion{Major: 1}, High: Microversion{Major: 1, Minor: 1}, Pinned: "1", ShouldFail: true},                            // 8
+}
+
+var discoverVersionTests = []struct {
+	Status     int
+	Versions   string
+	Version    APIVersion
+	Header     string
+	ShouldFail bool
+}{
+	{ // 0
+		Status:   http.StatusMultipleChoices,
+		Versions: testVersions,
+		Version: APIVersion{
+			ID:              "v1",
+			Status:          "CURRENT",
+			MinMicroversion: Microversion{Major: 1},
+			MaxMicroversion: Microversion{Major: 1, Minor: 1},
+			Microversion:    Microversion{Major: 1, Minor: 1},
+			Features:        Features{SecretConsumers: true},
+		},
+		Header: "key-manager 1.1",
+	},
+	{ // 1 - No microversions
+		Status:   http.StatusOK,
+		Versions: `{"versions":{"values":[{"id":"v1","status":"stable"}]}}`,
+		Version: APIVersion{
+			ID:              "v1",
+			Status:          "stable",
+			MinMicroversion: Microversion{Major: 1},
+			MaxMicroversion: Microversion{Major: 1},
+			Microversion:    Microversion{Major: 1},
+		},
+	},
+	{ // 2 - No version document
+		Status:   http.StatusNotFound,
+		Versions: `{"code":404,"title":"Not Found","description":"The resource could not be found."}`,
+		Version: APIVersion{
+			ID:              DefaultAPIVersion,
+			MinMicroversion: Microversion{Major: 1},
+			MaxMicroversion: Microversion{Major: 1},
+			Microversion:    Microversion{Major: 1},
+		},
+	},
+	{ // 3
+		Status:     http.StatusInternalServerError,
+		Versions:   `{"code":500,"title":"Internal Server Error","description":"Unexpected error."}`,
+		ShouldFail: true,
+	},
+	{ // 4
+		Status:     http.StatusForbidden,
+		Versions:   `{"code":403,"title":"Forbidden","description":"Access was denied to this resource."}`,
+		ShouldFail: true,
+	},
+}
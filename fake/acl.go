@@ -0,0 +1,132 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func (c *Client) GetSecretACL(ctx context.Context, id string) (*client.ACL, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	return c.getACL(id, false, xerror.ErrKeyNotFound)
+}
+
+func (c *Client) SetSecretACL(ctx context.Context, id string, acl client.ACL) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.setACL(id, false, acl, xerror.ErrKeyNotFound)
+}
+
+func (c *Client) UpdateSecretACL(ctx context.Context, id string, update client.ACLUpdate) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.updateACL(id, false, update, xerror.ErrKeyNotFound)
+}
+
+func (c *Client) DeleteSecretACL(ctx context.Context, id string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.deleteACL(id, false, xerror.ErrKeyNotFound)
+}
+
+func (c *Client) GetContainerACL(ctx context.Context, ref string) (*client.ACL, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	return c.getACL(ref, true, xerror.ErrContainerNotFound)
+}
+
+func (c *Client) SetContainerACL(ctx context.Context, ref string, acl client.ACL) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.setACL(ref, true, acl, xerror.ErrContainerNotFound)
+}
+
+func (c *Client) UpdateContainerACL(ctx context.Context, ref string, update client.ACLUpdate) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.updateACL(ref, true, update, xerror.ErrContainerNotFound)
+}
+
+func (c *Client) DeleteContainerACL(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.deleteACL(ref, true, xerror.ErrContainerNotFound)
+}
+
+func (c *Client) getACL(ref string, container bool, notFoundErr error) (*client.ACL, error) {
+	if !c.exists(ref, container) {
+		return nil, notFoundErr
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, false); err != nil {
+		return nil, err
+	}
+	acl, ok := c.fakeData.GetACL(ref)
+	if !ok {
+		return nil, notFoundErr
+	}
+	return &acl, nil
+}
+
+func (c *Client) setACL(ref string, container bool, acl client.ACL, notFoundErr error) error {
+	users := append([]string{}, acl.Read.Users...)
+	return c.modifyACL(ref, container, notFoundErr, func(client.ACL) client.ACL {
+		var replaced client.ACL
+		replaced.Read.Users = users
+		if acl.Read.ProjectAccess != nil {
+			projectAccess := *acl.Read.ProjectAccess
+			replaced.Read.ProjectAccess = &projectAccess
+		}
+		return replaced
+	})
+}
+
+func (c *Client) updateACL(ref string, container bool, update client.ACLUpdate, notFoundErr error) error {
+	return c.modifyACL(ref, container, notFoundErr, update.Apply)
+}
+
+func (c *Client) deleteACL(ref string, container bool, notFoundErr error) error {
+	if !c.exists(ref, container) {
+		return notFoundErr
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, true); err != nil {
+		return err
+	}
+	if !c.fakeData.DeleteACL(ref) {
+		return notFoundErr
+	}
+	return nil
+}
+
+func (c *Client) modifyACL(ref string, container bool, notFoundErr error, fn func(client.ACL) client.ACL) error {
+	if !c.exists(ref, container) {
+		return notFoundErr
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, true); err != nil {
+		return err
+	}
+	if !c.fakeData.UpdateACL(ref, fn) {
+		return notFoundErr
+	}
+	return nil
+}
+
+// exists reports whether the secret - or, if container
+// is true, the container - addressed by ref exists.
+func (c *Client) exists(ref string, container bool) bool {
+	if container {
+		_, ok := c.fakeData.GetContainer(ref)
+		return ok
+	}
+	_, ok := c.fakeData.GetByRef(ref)
+	return ok
+}
@@ -0,0 +1,87 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestSecretACL(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	secret, err := conn.Create(ctx, "my-key", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	id := secret.ID()
+
+	alice, bob := conn.AsUser("alice"), conn.AsUser("bob")
+	if _, err = alice.GetPayloadByID(ctx, id); err != nil {
+		t.Fatalf("Project member cannot read secret without ACL: %v", err)
+	}
+
+	if err = conn.SetSecretACL(ctx, id, client.PrivateACL("alice")); err != nil {
+		t.Fatalf("Failed to set ACL: %v", err)
+	}
+	for i, test := range aclTests {
+		user := conn
+		switch test.User {
+		case "alice":
+			user = alice
+		case "bob":
+			user = bob
+		}
+
+		_, err := user.GetPayloadByID(ctx, id)
+		if err == nil && !test.Read {
+			t.Fatalf("Test %d: '%s' can read the private secret", i, test.User)
+		}
+		if err != nil && (test.Read || !errors.Is(err, xerror.Forbidden)) {
+			t.Fatalf("Test %d: got error '%v' - read access: %v", i, err, test.Read)
+		}
+
+		err = user.UpdateSecretACL(ctx, id, client.ACLUpdate{})
+		if err == nil && !test.Write {
+			t.Fatalf("Test %d: '%s' can modify the ACL of the private secret", i, test.User)
+		}
+		if err != nil && test.Write {
+			t.Fatalf("Test %d: failed to modify ACL: %v", i, err)
+		}
+	}
+
+	enabled := true
+	if err = conn.UpdateSecretACL(ctx, id, client.ACLUpdate{ProjectAccess: &enabled}); err != nil {
+		t.Fatalf("Failed to update ACL: %v", err)
+	}
+	acl, err := conn.GetSecretACL(ctx, id)
+	if err != nil {
+		t.Fatalf("Failed to fetch ACL: %v", err)
+	}
+	if !acl.Read.HasProjectAccess() || !acl.Read.HasUser("alice") {
+		t.Fatalf("Got ACL %+v - want project access and user alice", acl.Read)
+	}
+	if _, err = bob.GetPayloadByID(ctx, id); err != nil {
+		t.Fatalf("Project member cannot read secret with project access: %v", err)
+	}
+
+	if err = conn.DeleteSecretACL(ctx, id); err != nil {
+		t.Fatalf("Failed to delete ACL: %v", err)
+	}
+	if _, err = conn.GetSecretACL(ctx, "missing"); !errors.Is(err, xerror.ErrKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrKeyNotFound)
+	}
+}
+
+var aclTests = []struct {
+	User  string
+	Read  bool
+	Write bool
+}{
+	{User: DefaultUser, Read: true, Write: true}, // 0 - The creator
+	{User: "alice", Read: true, Write: false},    // 1 - Listed in the ACL
+	{User: "bob", Read: false, Write: false},     // 2
+}
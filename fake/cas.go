@@ -0,0 +1,323 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func (c *Client) ListCAs(ctx context.Context, pluginName string) ([]client.CA, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	cas := []client.CA{}
+	for _, ca := range c.fakeData.ListCAs() {
+		if pluginName == "" || ca.PluginName == pluginName {
+			cas = append(cas, ca)
+		}
+	}
+	return cas, nil
+}
+
+func (c *Client) GetCA(ctx context.Context, ref string) (*client.CA, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	ca, ok := c.fakeData.GetCA(ref)
+	if !ok {
+		return nil, xerror.ErrCANotFound
+	}
+	return &ca, nil
+}
+
+func (c *Client) GetCACertificate(ctx context.Context, ref string) ([]byte, error) {
+	ca, err := c.GetCA(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ca.SigningCertificate()), nil
+}
+
+func (c *Client) GetCAIntermediates(ctx context.Context, ref string) ([]byte, error) {
+	ca, err := c.GetCA(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ca.Intermediates()), nil
+}
+
+func (c *Client) GetPreferredCA(ctx context.Context) (*client.CA, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	ca, ok := c.fakeData.PreferredCA()
+	if !ok {
+		return nil, xerror.ErrCANotFound
+	}
+	return &ca, nil
+}
+
+func (c *Client) SetPreferredCA(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	return c.fakeData.SetPreferredCA(ref)
+}
+
+func (c *Client) AddCAToProject(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.AddCAToProject(ref) {
+		return xerror.ErrCANotFound
+	}
+	return nil
+}
+
+func (c *Client) RemoveCAFromProject(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.RemoveCAFromProject(ref) {
+		return xerror.ErrCANotFound
+	}
+	return nil
+}
+
+func (c *Client) GetGlobalPreferredCA(ctx context.Context) (*client.CA, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	ca, ok := c.fakeData.GlobalPreferredCA()
+	if !ok {
+		return nil, xerror.ErrCANotFound
+	}
+	return &ca, nil
+}
+
+func (c *Client) SetGlobalPreferredCA(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.SetGlobalPreferredCA(ref) {
+		return xerror.ErrCANotFound
+	}
+	return nil
+}
+
+func (c *Client) UnsetGlobalPreferredCA(ctx context.Context) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	c.fakeData.UnsetGlobalPreferredCA()
+	return nil
+}
+
+// SetCAs replaces the certificate authorities with new
+// CAs with the given names. It resets the project CAs
+// and all CA preferences.
+func (f *FakeData) SetCAs(names ...string) {
+	cas := make([]*fakeCA, 0, len(names))
+	for _, name := range names {
+		cas = append(cas, mustNewFakeCA(name))
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.cas = cas
+	f.projectCAs, f.preferredCA, f.globalPreferredCA = nil, "", ""
+}
+
+// ListCAs returns the CAs available to DefaultProject:
+// its project CAs, if any, or all CAs.
+func (f *FakeData) ListCAs() []client.CA {
+	f.RLock()
+	defer f.RUnlock()
+
+	var cas []client.CA
+	for _, ca := range f.cas {
+		if len(f.projectCAs) == 0 || contains(f.projectCAs, ca.id) {
+			cas = append(cas, ca.info())
+		}
+	}
+	return cas
+}
+
+// GetCA returns the CA addressed by the given
+// CA reference or UUID.
+func (f *FakeData) GetCA(ref string) (ca client.CA, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+	if c, ok := f.ca(client.RefID(ref)); ok {
+		return c.info(), true
+	}
+	return ca, false
+}
+
+// PreferredCA returns the preferred CA of DefaultProject,
+// if any, or the global preferred CA.
+func (f *FakeData) PreferredCA() (ca client.CA, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+	if c, ok := f.ca(f.preferredCA); ok {
+		return c.info(), true
+	}
+	if c, ok := f.ca(f.globalPreferredCA); ok {
+		return c.info(), true
+	}
+	return ca, false
+}
+
+// SetPreferredCA makes the project CA addressed by the
+// given CA reference or UUID the preferred CA of
+// DefaultProject.
+func (f *FakeData) SetPreferredCA(ref string) error {
+	f.Lock()
+	defer f.Unlock()
+	id := client.RefID(ref)
+	if _, ok := f.ca(id); !ok {
+		return xerror.ErrCANotFound
+	}
+	if !contains(f.projectCAs, id) {
+		return xerror.NewError(http.StatusBadRequest, "Cannot set CA as a preferred CA as it is not a project CA.")
+	}
+	f.preferredCA = id
+	return nil
+}
+
+// AddCAToProject adds the CA addressed by the given CA
+// reference or UUID to the project CAs of DefaultProject.
+// It returns false if the CA does not exist.
+func (f *FakeData) AddCAToProject(ref string) bool {
+	f.Lock()
+	defer f.Unlock()
+	id := client.RefID(ref)
+	if _, ok := f.ca(id); !ok {
+		return false
+	}
+	if !contains(f.projectCAs, id) {
+		f.projectCAs = append(f.projectCAs, id)
+	}
+	if f.preferredCA == "" {
+		f.preferredCA = id
+	}
+	return true
+}
+
+// RemoveCAFromProject removes the CA addressed by the
+// given CA reference or UUID from the project CAs of
+// DefaultProject. It returns false if the CA is not
+// a project CA.
+func (f *FakeData) RemoveCAFromProject(ref string) bool {
+	f.Lock()
+	defer f.Unlock()
+	id := client.RefID(ref)
+	for i, projectCA := range f.projectCAs {
+		if projectCA == id {
+			f.projectCAs = append(f.projectCAs[:i:i], f.projectCAs[i+1:]...)
+			if f.preferredCA == id {
+				f.preferredCA = ""
+				if len(f.projectCAs) > 0 {
+					f.preferredCA = f.projectCAs[0]
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// GlobalPreferredCA returns the global preferred CA, if any.
+func (f *FakeData) GlobalPreferredCA() (ca client.CA, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+	if c, ok := f.ca(f.globalPreferredCA); ok {
+		return c.info(), true
+	}
+	return ca, false
+}
+
+// SetGlobalPreferredCA makes the CA addressed by the given
+// CA reference or UUID the global preferred CA. It returns
+// false if the CA does not exist.
+func (f *FakeData) SetGlobalPreferredCA(ref string) bool {
+	f.Lock()
+	defer f.Unlock()
+	id := client.RefID(ref)
+	if _, ok := f.ca(id); !ok {
+		return false
+	}
+	f.globalPreferredCA = id
+	return true
+}
+
+// UnsetGlobalPreferredCA removes the global preferred CA.
+func (f *FakeData) UnsetGlobalPreferredCA() {
+	f.Lock()
+	f.globalPreferredCA = ""
+	f.Unlock()
+}
+
+// issuingCA returns the CA issuing the certificate of an
+// order requesting the CA with the given UUID. If id is
+// empty, the preferred CA or the first CA is used.
+//
+// f must be locked.
+func (f *FakeData) issuingCA(id string) (*fakeCA, error) {
+	if id == "" {
+		for _, preferred := range []string{f.preferredCA, f.globalPreferredCA} {
+			if ca, ok := f.ca(preferred); ok {
+				return ca, nil
+			}
+		}
+		if len(f.projectCAs) > 0 {
+			id = f.projectCAs[0]
+		} else if len(f.cas) > 0 {
+			return f.cas[0], nil
+		}
+	}
+
+	ca, ok := f.ca(id)
+	if !ok {
+		return nil, errors.New("the ca_id provided in the request is invalid")
+	}
+	if len(f.projectCAs) > 0 && !contains(f.projectCAs, id) {
+		return nil, errors.New("the ca_id provided in the request is not a project CA")
+	}
+	return ca, nil
+}
+
+// ca returns the CA with the given UUID.
+//
+// f must be locked.
+func (f *FakeData) ca(id string) (*fakeCA, bool) {
+	if id == "" {
+		return nil, false
+	}
+	for _, ca := range f.cas {
+		if ca.id == id {
+			return ca, true
+		}
+	}
+	return nil, false
+}
+
+func mustNewFakeCA(name string) *fakeCA {
+	ca, err := newFakeCA(name)
+	if err != nil {
+		panic(err)
+	}
+	return ca
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package fake
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestCAs(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil, WithCAs("Root CA", "Issuing CA"))
+
+	cas, err := conn.ListCAs(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to list CAs: %v", err)
+	}
+	if len(cas) != 2 || cas[0].Name() != "Root CA" || cas[1].Name() != "Issuing CA" {
+		t.Fatalf("Got %+v - want the CAs 'Root CA' and 'Issuing CA'", cas)
+	}
+	root, issuing := cas[0], cas[1]
+
+	cert, err := conn.GetCACertificate(ctx, issuing.ID())
+	if err != nil {
+		t.Fatalf("Failed to fetch CA certificate: %v", err)
+	}
+	if name := parseCertificate(t, cert).Subject.CommonName; name != "Issuing CA" {
+		t.Fatalf("Got CA certificate of '%s' - want 'Issuing CA'", name)
+	}
+	if _, err = conn.GetCA(ctx, "missing"); !errors.Is(err, xerror.ErrCANotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrCANotFound)
+	}
+
+	if _, err = conn.GetPreferredCA(ctx); !errors.Is(err, xerror.ErrCANotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrCANotFound)
+	}
+	if err = conn.SetPreferredCA(ctx, issuing.ID()); err == nil {
+		t.Fatal("Set a CA that is not a project CA as preferred CA")
+	}
+	if err = conn.SetGlobalPreferredCA(ctx, root.ID()); err != nil {
+		t.Fatalf("Failed to set global preferred CA: %v", err)
+	}
+	if preferred, err := conn.GetPreferredCA(ctx); err != nil || preferred.ID() != root.ID() {
+		t.Fatalf("Got preferred CA %v and error '%v' - want the global preferred CA", preferred, err)
+	}
+
+	// The first project CA becomes the preferred CA
+	// and restricts the CAs available to the project.
+	if err = conn.AddCAToProject(ctx, issuing.CAID); err != nil {
+		t.Fatalf("Failed to add CA to project: %v", err)
+	}
+	if preferred, err := conn.GetPreferredCA(ctx); err != nil || preferred.ID() != issuing.ID() {
+		t.Fatalf("Got preferred CA %v and error '%v' - want the project CA", preferred, err)
+	}
+	if cas, err = conn.ListCAs(ctx, ""); err != nil || len(cas) != 1 || cas[0].ID() != issuing.ID() {
+		t.Fatalf("Got CAs %+v and error '%v' - want the project CA", cas, err)
+	}
+
+	for i, test := range []struct {
+		CAID   string
+		Issuer string
+	}{
+		{CAID: "", Issuer: "Issuing CA"},           // 0 - Preferred CA
+		{CAID: issuing.ID(), Issuer: "Issuing CA"}, // 1
+		{CAID: root.ID()},                          // 2 - Not a project CA
+	} {
+		issuer := issueCertificate(t, conn, test.CAID)
+		if issuer != test.Issuer {
+			t.Fatalf("Test %d: got issuer '%s' - want '%s'", i, issuer, test.Issuer)
+		}
+	}
+
+	if err = conn.RemoveCAFromProject(ctx, issuing.ID()); err != nil {
+		t.Fatalf("Failed to remove CA from project: %v", err)
+	}
+	if err = conn.RemoveCAFromProject(ctx, issuing.ID()); !errors.Is(err, xerror.ErrCANotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrCANotFound)
+	}
+	if err = conn.UnsetGlobalPreferredCA(ctx); err != nil {
+		t.Fatalf("Failed to unset global preferred CA: %v", err)
+	}
+	if _, err = conn.GetGlobalPreferredCA(ctx); !errors.Is(err, xerror.ErrCANotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrCANotFound)
+	}
+	if issuer := issueCertificate(t, conn, ""); issuer != "Root CA" {
+		t.Fatalf("Got issuer '%s' - want the first CA 'Root CA'", issuer)
+	}
+}
+
+// issueCertificate orders a certificate from the CA with
+// the given UUID and returns the name of its issuer. It
+// returns an empty name if the order failed.
+func issueCertificate(t *testing.T, conn *Client, caID string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	order, err := conn.SubmitOrder(ctx, client.AsymmetricOrder{Algorithm: "rsa", BitLength: 2048})
+	if err != nil {
+		t.Fatalf("Failed to submit order: %v", err)
+	}
+	if order, err = conn.WaitForOrder(ctx, order.OrderRef, time.Millisecond); err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+
+	order, err = conn.SubmitOrder(ctx, client.CertificateOrder{
+		RequestType:        client.CertificateRequestStoredKey,
+		SubjectDN:          "CN=example.com",
+		SourceContainerRef: order.ContainerRef,
+		CAID:               caID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit order: %v", err)
+	}
+	if order, err = conn.WaitForOrder(ctx, order.OrderRef, time.Millisecond); err != nil {
+		return ""
+	}
+	container, err := conn.GetContainer(ctx, order.ContainerRef)
+	if err != nil {
+		t.Fatalf("Failed to fetch certificate container: %v", err)
+	}
+	ref, _ := container.SecretRef(client.ContainerSecretCertificate)
+	cert, err := conn.GetPayloadByID(ctx, ref)
+	if err != nil {
+		t.Fatalf("Failed to fetch certificate: %v", err)
+	}
+	return parseCertificate(t, cert).Issuer.CommonName
+}
+
+func parseCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatal("Certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert
+}
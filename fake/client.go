@@ -2,16 +2,141 @@ package fake
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
 )
 
-func New(ctx context.Context, fakeData map[string][]byte) (client.Conn, error) {
-	return newConnection(ctx, fakeData)
+// Option customizes the behavior of a fake connection.
+type Option func(*Client)
+
+// WithNameResolution sets how the fake resolves secret
+// names. Defaults to client.NameResolutionFirst.
+func WithNameResolution(mode client.NameResolution) Option {
+	return func(c *Client) { c.nameResolution = mode }
+}
+
+// WithUser sets the ID of the simulated Keystone user
+// performing the requests. Defaults to DefaultUser.
+//
+// The user is recorded as creator of new secrets and
+// containers, and ACLs are enforced against it.
+func WithUser(user string) Option {
+	return func(c *Client) { c.user = user }
+}
+
+// WithSecretStores sets the secret stores of the simulated
+// multiple backend deployment. Stores without a reference
+// get one assigned. If no store is marked as global default,
+// the first one becomes the global default.
+//
+// By default, the fake has a single software-only store.
+func WithSecretStores(stores ...client.SecretStore) Option {
+	return func(c *Client) { c.stores = stores }
+}
+
+// WithQuotas sets the default quotas of the simulated
+// deployment. By default, all quotas are unlimited.
+//
+// The fake enforces the effective quotas of DefaultProject
+// and fails with an error matching xerror.QuotaExceeded
+// once a quota has been reached.
+func WithQuotas(quotas client.Quotas) Option {
+	return func(c *Client) { c.quotas = &quotas }
+}
+
+// WithMaxMicroversion sets the highest key-manager microversion
+// advertised by the fake. Defaults to client.MaxMicroversion.
+//
+// Like a connection to a real server, the fake negotiates the
+// highest microversion supported by both sides and fails with
+// xerror.ErrNotSupported if an operation requires a newer one,
+// e.g. secret consumers with microversion 1.0.
+func WithMaxMicroversion(version client.Microversion) Option {
+	return func(c *Client) { c.maxMicroversion = version }
+}
+
+// WithCAs sets the names of the certificate authorities of
+// the fake. Certificate orders without CA are issued by the
+// first CA unless a preferred CA is set.
+//
+// By default, the fake has a single CA named DefaultCAName.
+func WithCAs(names ...string) Option {
+	return func(c *Client) { c.cas = names }
+}
+
+func New(ctx context.Context, fakeData map[string][]byte, opts ...Option) (client.Conn, error) {
+	return newConnection(ctx, fakeData, opts...)
+}
+
+func newConnection(ctx context.Context, fakeData map[string][]byte, opts ...Option) (client.Conn, error) {
+	c := &Client{
+		user:            DefaultUser,
+		closed:          new(atomic.Bool),
+		maxMicroversion: client.MaxMicroversion,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.version = newAPIVersion(c.maxMicroversion)
+	c.fakeData = newFakeData(fakeData, c.user)
+	if len(c.stores) > 0 {
+		c.fakeData.SetSecretStores(c.stores)
+	}
+	if c.quotas != nil {
+		c.fakeData.SetQuotas(*c.quotas)
+	}
+	if len(c.cas) > 0 {
+		c.fakeData.SetCAs(c.cas...)
+	}
+	return c, nil
+}
+
+// APIVersion returns the API version advertised by the
+// fake - see WithMaxMicroversion.
+func (c *Client) APIVersion() client.APIVersion {
+	return c.version
+}
+
+// newAPIVersion returns the API version negotiated with a
+// server supporting the microversions 1.0 up to high.
+func newAPIVersion(high client.Microversion) client.APIVersion {
+	version := client.APIVersion{
+		ID:              client.DefaultAPIVersion,
+		Status:          "CURRENT",
+		MinMicroversion: client.Microversion{Major: 1},
+		MaxMicroversion: high,
+		Microversion:    high,
+	}
+	if client.MaxMicroversion.Less(high) {
+		version.Microversion = client.MaxMicroversion
+	}
+	version.Features = version.Microversion.Features()
+	return version
+}
+
+// AsUser returns a connection to the same fake data
+// that performs requests as the given user.
+func (c *Client) AsUser(user string) *Client {
+	conn := *c
+	conn.user = user
+	return &conn
+}
+
+// Close closes the connection and all connections
+// returned by AsUser. Any subsequent request fails
+// with xerror.ErrClosed.
+func (c *Client) Close(ctx context.Context) error {
+	c.closed.Store(true)
+	return nil
 }
 
-func newConnection(ctx context.Context, fakeData map[string][]byte) (client.Conn, error) {
-	return &Client{
-		fakeData: NewFakeData(fakeData),
-	}, nil
+// checkOpen returns xerror.ErrClosed if the
+// connection has been closed.
+func (c *Client) checkOpen() error {
+	if c.closed != nil && c.closed.Load() {
+		return xerror.ErrClosed
+	}
+	return nil
 }
@@ -0,0 +1,72 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// newTestConn returns a new fake connection
+// with the given secrets and options.
+func newTestConn(t *testing.T, secrets map[string][]byte, opts ...Option) *Client {
+	t.Helper()
+
+	conn, err := New(context.Background(), secrets, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create fake connection: %v", err)
+	}
+	return conn.(*Client)
+}
+
+func TestAsUser(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+	alice, bob := conn.AsUser("alice"), conn.AsUser("bob")
+
+	secret, err := alice.Create(ctx, "my-key", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	if secret.CreatorID != "alice" {
+		t.Fatalf("Got creator '%s' - want 'alice'", secret.CreatorID)
+	}
+	if err = alice.SetSecretACL(ctx, secret.ID(), client.PrivateACL("bob")); err != nil {
+		t.Fatalf("Failed to set ACL: %v", err)
+	}
+
+	if _, err = bob.GetPayloadByID(ctx, secret.ID()); err != nil {
+		t.Fatalf("Listed user cannot read secret: %v", err)
+	}
+	for i, user := range []*Client{conn, bob} {
+		if err = user.DeleteSecretByID(ctx, secret.ID()); !errors.Is(err, xerror.Forbidden) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, xerror.Forbidden)
+		}
+	}
+	if _, err = conn.GetPayloadByID(ctx, secret.ID()); !errors.Is(err, xerror.Forbidden) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.Forbidden)
+	}
+	if err = alice.DeleteSecretByID(ctx, secret.ID()); err != nil {
+		t.Fatalf("Creator cannot delete private secret: %v", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, map[string][]byte{"my-key": []byte("Hello World")})
+	alice := conn.AsUser("alice")
+
+	if err := alice.Close(ctx); err != nil {
+		t.Fatalf("Failed to close connection: %v", err)
+	}
+	for i, user := range []*Client{conn, alice, conn.AsUser("bob")} {
+		if _, err := user.GetSecret(ctx, "my-key"); !errors.Is(err, xerror.ErrClosed) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, xerror.ErrClosed)
+		}
+	}
+	if err := conn.Close(ctx); err != nil {
+		t.Fatalf("Closing a closed connection failed: %v", err)
+	}
+}
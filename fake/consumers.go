@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// RegisterContainerConsumer registers the given consumer.
+// Like Barbican, registering the same consumer twice only
+// updates its timestamp.
+func (c *Client) RegisterContainerConsumer(ctx context.Context, ref string, consumer client.ContainerConsumer) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, false); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateContainer(ref, func(container *client.BarbicanContainer) error {
+		now := newCreationTime()
+		for i, existing := range container.Consumers {
+			if existing.Name == consumer.Name && existing.URL == consumer.URL {
+				container.Consumers[i].Updated = now
+				return nil
+			}
+		}
+		if err := c.fakeData.checkQuota(quotaConsumers); err != nil {
+			return err
+		}
+		container.Consumers = append(container.Consumers, client.ContainerConsumer{
+			Name:    consumer.Name,
+			URL:     consumer.URL,
+			Created: now,
+			Updated: now,
+			Status:  "ACTIVE",
+		})
+		return nil
+	})
+}
+
+func (c *Client) ListContainerConsumers(ctx context.Context, ref string) ([]client.ContainerConsumer, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	container, err := c.GetContainer(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return append([]client.ContainerConsumer{}, container.Consumers...), nil
+}
+
+func (c *Client) RemoveContainerConsumer(ctx context.Context, ref string, consumer client.ContainerConsumer) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, false); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateContainer(ref, func(container *client.BarbicanContainer) error {
+		for i, existing := range container.Consumers {
+			if existing.Name == consumer.Name && existing.URL == consumer.URL {
+				container.Consumers = append(container.Consumers[:i:i], container.Consumers[i+1:]...)
+				return nil
+			}
+		}
+		return xerror.ErrConsumerNotFound
+	})
+}
+
+// RegisterSecretConsumer registers the given consumer.
+// Like Barbican, registering the same consumer twice only
+// updates its timestamp.
+func (c *Client) RegisterSecretConsumer(ctx context.Context, id string, consumer client.SecretConsumer) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.version.Features.SecretConsumers {
+		return xerror.ErrNotSupported
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateSecretConsumers(id, func(consumers []client.SecretConsumer) ([]client.SecretConsumer, error) {
+		now := newCreationTime()
+		for i, existing := range consumers {
+			if sameSecretConsumer(existing, consumer) {
+				consumers[i].Updated = now
+				return consumers, nil
+			}
+		}
+		if err := c.fakeData.checkQuota(quotaConsumers); err != nil {
+			return nil, err
+		}
+		return append(consumers, client.SecretConsumer{
+			Service:      consumer.Service,
+			ResourceType: consumer.ResourceType,
+			ResourceID:   consumer.ResourceID,
+			Created:      now,
+			Updated:      now,
+			Status:       "ACTIVE",
+		}), nil
+	})
+}
+
+func (c *Client) ListSecretConsumers(ctx context.Context, id string) ([]client.SecretConsumer, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if !c.version.Features.SecretConsumers {
+		return nil, xerror.ErrNotSupported
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return nil, err
+	}
+	return c.fakeData.GetSecretConsumers(id)
+}
+
+func (c *Client) RemoveSecretConsumer(ctx context.Context, id string, consumer client.SecretConsumer) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.version.Features.SecretConsumers {
+		return xerror.ErrNotSupported
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateSecretConsumers(id, func(consumers []client.SecretConsumer) ([]client.SecretConsumer, error) {
+		for i, existing := range consumers {
+			if sameSecretConsumer(existing, consumer) {
+				return append(consumers[:i:i], consumers[i+1:]...), nil
+			}
+		}
+		return nil, xerror.ErrConsumerNotFound
+	})
+}
+
+func sameSecretConsumer(a, b client.SecretConsumer) bool {
+	return a.Service == b.Service && a.ResourceType == b.ResourceType && a.ResourceID == b.ResourceID
+}
@@ -0,0 +1,79 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestContainerConsumers(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	container, err := conn.CreateContainer(ctx, client.GenericContainer{Name: "my-secrets"})
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	consumer := client.ContainerConsumer{Name: "octavia", URL: "https://octavia/lb/1"}
+	for i := 0; i < 2; i++ {
+		if err = conn.RegisterContainerConsumer(ctx, container.ContainerRef, consumer); err != nil {
+			t.Fatalf("Failed to register consumer: %v", err)
+		}
+	}
+	consumers, err := conn.ListContainerConsumers(ctx, container.ContainerRef)
+	if err != nil {
+		t.Fatalf("Failed to list consumers: %v", err)
+	}
+	if len(consumers) != 1 || consumers[0].Name != consumer.Name || consumers[0].URL != consumer.URL {
+		t.Fatalf("Got consumers %v - want a single %v", consumers, consumer)
+	}
+
+	if err = conn.RemoveContainerConsumer(ctx, container.ContainerRef, consumer); err != nil {
+		t.Fatalf("Failed to remove consumer: %v", err)
+	}
+	if err = conn.RemoveContainerConsumer(ctx, container.ContainerRef, consumer); !errors.Is(err, xerror.ErrConsumerNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrConsumerNotFound)
+	}
+	if err = conn.RemoveContainerConsumer(ctx, "missing", consumer); !errors.Is(err, xerror.ErrContainerNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrContainerNotFound)
+	}
+}
+
+func TestSecretConsumers(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	secret, err := conn.Create(ctx, "my-key", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	consumer := client.SecretConsumer{Service: "image", ResourceType: "image", ResourceID: "1"}
+	if err = conn.RegisterSecretConsumer(ctx, secret.ID(), consumer); err != nil {
+		t.Fatalf("Failed to register consumer: %v", err)
+	}
+	consumers, err := conn.ListSecretConsumers(ctx, secret.ID())
+	if err != nil {
+		t.Fatalf("Failed to list consumers: %v", err)
+	}
+	if len(consumers) != 1 || consumers[0].ResourceID != consumer.ResourceID {
+		t.Fatalf("Got consumers %v - want a single %v", consumers, consumer)
+	}
+
+	if err = conn.RemoveSecretConsumer(ctx, secret.ID(), consumer); err != nil {
+		t.Fatalf("Failed to remove consumer: %v", err)
+	}
+	if err = conn.RemoveSecretConsumer(ctx, secret.ID(), consumer); !errors.Is(err, xerror.ErrConsumerNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrConsumerNotFound)
+	}
+	if err = conn.RemoveSecretConsumer(ctx, "missing", consumer); !errors.Is(err, xerror.ErrKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrKeyNotFound)
+	}
+
+	old := newTestConn(t, nil, WithMaxMicroversion(client.Microversion{Major: 1}))
+	if err = old.RegisterSecretConsumer(ctx, secret.ID(), consumer); !errors.Is(err, xerror.ErrNotSupported) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrNotSupported)
+	}
+}
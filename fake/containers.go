@@ -0,0 +1,185 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// containerSecretNames lists the secret reference names
+// allowed - and whether they are required - per container
+// type. Generic containers accept any name.
+var containerSecretNames = map[client.ContainerType]map[string]bool{
+	client.ContainerTypeRSA: {
+		client.ContainerSecretPublicKey:            true,
+		client.ContainerSecretPrivateKey:           true,
+		client.ContainerSecretPrivateKeyPassphrase: false,
+	},
+	client.ContainerTypeCertificate: {
+		client.ContainerSecretCertificate:          true,
+		client.ContainerSecretPrivateKey:           false,
+		client.ContainerSecretPrivateKeyPassphrase: false,
+		client.ContainerSecretIntermediates:        false,
+	},
+}
+
+func (c *Client) CreateContainer(ctx context.Context, spec client.ContainerSpec) (*client.BarbicanContainer, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	request := spec.CreateRequest()
+	if err := validateContainer(request); err != nil {
+		return nil, err
+	}
+	if err := c.fakeData.CheckQuota(quotaContainers); err != nil {
+		return nil, err
+	}
+
+	container := client.BarbicanContainer{
+		Consumers:    []client.ContainerConsumer{},
+		ContainerRef: newRef("containers"),
+		Created:      newCreationTime(),
+		CreatorID:    c.user,
+		Name:         request.Name,
+		SecretRefs:   append([]client.ContainerSecretRef{}, request.SecretRefs...),
+		Status:       "ACTIVE",
+		Type:         request.Type,
+		Updated:      newCreationTime(),
+	}
+	c.fakeData.SetContainer(container)
+	return &container, nil
+}
+
+func (c *Client) GetContainer(ctx context.Context, ref string) (*client.BarbicanContainer, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	container, ok := c.fakeData.GetContainer(ref)
+	if !ok {
+		return nil, xerror.ErrContainerNotFound
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, false); err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+func (c *Client) DeleteContainer(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, true); err != nil {
+		return err
+	}
+	if !c.fakeData.DeleteContainer(ref) {
+		return xerror.ErrContainerNotFound
+	}
+	return nil
+}
+
+func (c *Client) AddContainerSecret(ctx context.Context, ref string, secret client.ContainerSecretRef) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if secret.SecretRef == "" {
+		return xerror.NewError(http.StatusBadRequest, "couldn't add secret. Provided object does not match schema 'Container Secret': 'secret_ref' is a required property")
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateContainer(ref, func(container *client.BarbicanContainer) error {
+		if container.Type != client.ContainerTypeGeneric {
+			return xerror.NewError(http.StatusBadRequest, "couldn't add secret. Only 'generic' containers can be modified")
+		}
+		for _, s := range container.SecretRefs {
+			if s == secret {
+				return xerror.NewError(http.StatusConflict, "couldn't add secret. Secret reference already exists in container")
+			}
+		}
+		container.SecretRefs = append(container.SecretRefs, secret)
+		return nil
+	})
+}
+
+func (c *Client) RemoveContainerSecret(ctx context.Context, ref string, secret client.ContainerSecretRef) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(ref, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateContainer(ref, func(container *client.BarbicanContainer) error {
+		if container.Type != client.ContainerTypeGeneric {
+			return xerror.NewError(http.StatusBadRequest, "couldn't remove secret. Only 'generic' containers can be modified")
+		}
+		for i, s := range container.SecretRefs {
+			if s == secret {
+				container.SecretRefs = append(container.SecretRefs[:i:i], container.SecretRefs[i+1:]...)
+				return nil
+			}
+		}
+		return xerror.NewError(http.StatusNotFound, "secret reference does not exist in container")
+	})
+}
+
+// ListContainers returns a new ContainerIterator over
+// all fake containers, newest first.
+func (c *Client) ListContainers(ctx context.Context) (client.ContainerIterator, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan client.BarbicanContainer, 10)
+
+	go func() {
+		defer close(values)
+		for _, container := range c.fakeData.ListContainers() {
+			select {
+			case values <- container:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &ContainerIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// validateContainer mirrors the schema validation
+// Barbican applies to container create requests.
+func validateContainer(request client.ContainerCreateRequest) error {
+	const prefix = "couldn't create. Provided object does not match schema 'Container': "
+	if request.Type != client.ContainerTypeGeneric && containerSecretNames[request.Type] == nil {
+		return xerror.NewError(http.StatusBadRequest, fmt.Sprintf(prefix+"'%s' is not one of ['generic', 'rsa', 'certificate']", request.Type))
+	}
+
+	seen := make(map[string]bool, len(request.SecretRefs))
+	for _, ref := range request.SecretRefs {
+		if ref.SecretRef == "" {
+			return xerror.NewError(http.StatusBadRequest, prefix+"'secret_ref' is a required property")
+		}
+		if request.Type == client.ContainerTypeGeneric {
+			continue
+		}
+		if _, ok := containerSecretNames[request.Type][ref.Name]; !ok {
+			return xerror.NewError(http.StatusBadRequest, fmt.Sprintf(prefix+"invalid secret name '%s' for container type '%s'", ref.Name, request.Type))
+		}
+		if seen[ref.Name] {
+			return xerror.NewError(http.StatusBadRequest, fmt.Sprintf(prefix+"duplicate secret name '%s'", ref.Name))
+		}
+		seen[ref.Name] = true
+	}
+	for name, required := range containerSecretNames[request.Type] {
+		if required && !seen[name] {
+			return xerror.NewError(http.StatusBadRequest, fmt.Sprintf(prefix+"secret '%s' is required for container type '%s'", name, request.Type))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestCreateContainer(t *testing.T) {
+	conn := newTestConn(t, nil)
+	for i, test := range createContainerTests {
+		container, err := conn.CreateContainer(context.Background(), test.Spec)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but created container %+v", i, *container)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to create container: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		stored, err := conn.GetContainer(context.Background(), container.ContainerRef)
+		if err != nil {
+			t.Fatalf("Test %d: failed to fetch created container: %v", i, err)
+		}
+		if stored.CreatorID != DefaultUser || stored.Type != test.Spec.CreateRequest().Type {
+			t.Fatalf("Test %d: got container %+v", i, *stored)
+		}
+	}
+}
+
+func TestContainerSecrets(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	generic, err := conn.CreateContainer(ctx, client.GenericContainer{Name: "generic"})
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	secret := client.ContainerSecretRef{Name: "key", SecretRef: newRef("secrets")}
+	if err = conn.AddContainerSecret(ctx, generic.ContainerRef, secret); err != nil {
+		t.Fatalf("Failed to add secret: %v", err)
+	}
+	if err = conn.AddContainerSecret(ctx, generic.ContainerRef, secret); !errors.Is(err, xerror.Conflict) {
+		t.Fatalf("Adding a secret twice: got error '%v' - want a conflict", err)
+	}
+	if err = conn.RemoveContainerSecret(ctx, generic.ContainerRef, secret); err != nil {
+		t.Fatalf("Failed to remove secret: %v", err)
+	}
+	if err = conn.RemoveContainerSecret(ctx, generic.ContainerRef, secret); !errors.Is(err, xerror.NotFound) || errors.Is(err, xerror.ErrContainerNotFound) {
+		t.Fatalf("Removing a missing secret: got error '%v' - want a not found error other than '%v'", err, xerror.ErrContainerNotFound)
+	}
+
+	rsa, err := conn.CreateContainer(ctx, client.RSAContainer{PublicKeyRef: newRef("secrets"), PrivateKeyRef: newRef("secrets")})
+	if err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	if err = conn.AddContainerSecret(ctx, rsa.ContainerRef, secret); err == nil {
+		t.Fatal("Modified an rsa container")
+	}
+
+	if err = conn.DeleteContainer(ctx, generic.ContainerRef); err != nil {
+		t.Fatalf("Failed to delete container: %v", err)
+	}
+	for i, err := range []error{
+		conn.DeleteContainer(ctx, generic.ContainerRef),
+		conn.AddContainerSecret(ctx, generic.ContainerRef, secret),
+		conn.RemoveContainerSecret(ctx, generic.ContainerRef, secret),
+	} {
+		if !errors.Is(err, xerror.ErrContainerNotFound) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, xerror.ErrContainerNotFound)
+		}
+	}
+}
+
+var createContainerTests = []struct {
+	Spec       client.ContainerSpec
+	ShouldFail bool
+}{
+	{Spec: client.GenericContainer{Name: "empty"}},                                                                                     // 0
+	{Spec: client.RSAContainer{PublicKeyRef: "a", PrivateKeyRef: "b"}},                                                                 // 1
+	{Spec: client.RSAContainer{PublicKeyRef: "a"}, ShouldFail: true},                                                                   // 2 - No private key
+	{Spec: client.CertificateContainer{CertificateRef: "a"}},                                                                           // 3
+	{Spec: client.CertificateContainer{PrivateKeyRef: "a"}, ShouldFail: true},                                                          // 4 - No certificate
+	{Spec: client.GenericContainer{SecretRefs: []client.ContainerSecretRef{{Name: "a"}}}, ShouldFail: true},                            // 5 - No secret ref
+	{Spec: client.GenericContainer{SecretRefs: []client.ContainerSecretRef{{Name: "a", SecretRef: "a"}, {Name: "a", SecretRef: "b"}}}}, // 6
+}
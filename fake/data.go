@@ -1,63 +1,227 @@
 package fake
 
 import (
+	"crypto/rand"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
 )
 
 const (
 	timeFormat = "2006-01-02T15:04:05"
+
+	// endpoint is the Barbican endpoint the fake references point to.
+	endpoint = "http://localhost:9311"
 )
 
 func newCreationTime() string {
-	return time.Now().Format(timeFormat)
+	return time.Now().UTC().Format(timeFormat)
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// newRef returns a new reference URL in the given collection,
+// e.g. "secrets", that looks like a real Barbican reference.
+func newRef(collection string) string {
+	return endpoint + "/v1/" + collection + "/" + newUUID()
 }
 
 func NewFakeData(d map[string][]byte) *FakeData {
+	return newFakeData(d, "")
+}
+
+// newFakeData returns new fake data holding the given
+// secrets created by the given user.
+func newFakeData(d map[string][]byte, creator string) *FakeData {
 	f := &FakeData{
-		data: make(map[string]client.BarbicanSecretWithPayload),
+		data:       make(map[string]client.BarbicanSecretWithPayload),
+		metadata:   make(map[string]map[string]string),
+		consumers:  make(map[string][]client.SecretConsumer),
+		containers: make(map[string]client.BarbicanContainer),
+		acls:       make(map[string]client.ACL),
+		orders:     make(map[string]*order),
+
+		cas: []*fakeCA{mustNewFakeCA(DefaultCAName)},
+
+		stores:       []client.SecretStore{newSecretStore("Software Only Crypto", "store_crypto", "simple_crypto", true)},
+		secretStores: make(map[string]string),
+
+		quotas: client.Quotas{
+			Secrets:    client.UnlimitedQuota,
+			Orders:     client.UnlimitedQuota,
+			Containers: client.UnlimitedQuota,
+			Consumers:  client.UnlimitedQuota,
+			CAs:        client.UnlimitedQuota,
+		},
+		projectQuotas: make(map[string]client.ProjectQuotas),
 	}
 	for name, payload := range d {
-		f.Set(name, NewSecret(name, payload))
+		secret := NewSecret(name, payload)
+		secret.Secret.CreatorID = creator
+		f.Set(name, secret)
 	}
 	return f
 }
 
 func NewSecret(name string, payload []byte) client.BarbicanSecretWithPayload {
+	return newSecret(client.SecretCreateRequest{
+		Name:               name,
+		Algorithm:          "aes",
+		BitLength:          256,
+		Mode:               "cbc",
+		PayloadContentType: "application/octet-stream",
+		SecretType:         "opaque",
+	}, payload)
+}
+
+// newSecret returns a new ACTIVE secret with the given
+// payload and the metadata of the given request.
+func newSecret(request client.SecretCreateRequest, payload []byte) client.BarbicanSecretWithPayload {
+	secret := client.BarbicanSecret{
+		ContentTypes: map[string]string{"default": request.PayloadContentType},
+		Created:      newCreationTime(),
+		Name:         request.Name,
+		SecretRef:    newRef("secrets"),
+		SecretType:   request.SecretType,
+		Status:       "ACTIVE",
+		Updated:      newCreationTime(),
+	}
+	if request.Algorithm != "" {
+		secret.Algorithm = request.Algorithm
+	}
+	if request.BitLength != 0 {
+		secret.BitLength = request.BitLength
+	}
+	if request.Mode != "" {
+		secret.Mode = request.Mode
+	}
+	if request.Expiration != "" {
+		secret.Expiration = request.Expiration
+	}
 	return client.BarbicanSecretWithPayload{
-		Secret: client.BarbicanSecret{
-			Algorithm:    "aes",
-			BitLength:    256,
-			ContentTypes: map[string]string{"default": "application/octet-stream"},
-			Created:      newCreationTime(),
-			Mode:         "cbc",
-			Name:         name,
-			SecretType:   "opaque",
-			Status:       "ACTIVE",
-			Updated:      newCreationTime(),
-		},
+		Secret:  secret,
 		Payload: payload,
 	}
 }
 
+// Set stores the given secret under the given name,
+// replacing any secret with the same name.
 func (f *FakeData) Set(name string, secret client.BarbicanSecretWithPayload) {
 	f.Lock()
-	f.data[name] = secret
+	for id, s := range f.data {
+		if s.Secret.Name == name {
+			f.remove(id)
+		}
+	}
+	f.put(secret)
 	f.Unlock()
 }
 
+// Add stores the given secret under its secret reference.
+// Unlike Set, it does not replace secrets with the same name.
+func (f *FakeData) Add(secret client.BarbicanSecretWithPayload) {
+	f.Lock()
+	f.put(secret)
+	f.Unlock()
+}
+
+// AddIfAbsent stores the given secret under its secret
+// reference if and only if no secret with the same name
+// exists. It reports whether the secret has been stored.
+func (f *FakeData) AddIfAbsent(secret client.BarbicanSecretWithPayload) bool {
+	f.Lock()
+	defer f.Unlock()
+	for _, s := range f.data {
+		if s.Secret.Name == secret.Secret.Name {
+			return false
+		}
+	}
+	f.put(secret)
+	return true
+}
+
+// Get returns the first secret with the given name, if any.
 func (f *FakeData) Get(name string) (secret client.BarbicanSecretWithPayload, exist bool) {
+	if secrets := f.FindByName(name); len(secrets) > 0 {
+		return secrets[0], true
+	}
+	return
+}
+
+// FindByName returns all secrets with the given name,
+// oldest first.
+func (f *FakeData) FindByName(name string) []client.BarbicanSecretWithPayload {
 	f.RLock()
-	secret, exist = f.data[name]
+	var secrets []client.BarbicanSecretWithPayload
+	for _, s := range f.data {
+		if s.Secret.Name == name {
+			secrets = append(secrets, s)
+		}
+	}
+	f.RUnlock()
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].Secret.Created != secrets[j].Secret.Created {
+			return secrets[i].Secret.Created < secrets[j].Secret.Created
+		}
+		return secrets[i].Secret.SecretRef < secrets[j].Secret.SecretRef
+	})
+	return secrets
+}
+
+// GetByRef returns the secret addressed by the given
+// secret reference or UUID, if any.
+func (f *FakeData) GetByRef(ref string) (secret client.BarbicanSecretWithPayload, exist bool) {
+	f.RLock()
+	secret, exist = f.data[client.RefID(ref)]
 	f.RUnlock()
 	return
 }
 
-func (f *FakeData) Delete(name string) {
+// Delete deletes the secret addressed by the given
+// secret reference or UUID, if any.
+func (f *FakeData) Delete(ref string) (exist bool) {
 	f.Lock()
-	delete(f.data, name)
+	id := client.RefID(ref)
+	_, exist = f.data[id]
+	f.remove(id)
 	f.Unlock()
+	return
+}
+
+// put stores the given secret in the current
+// secret store of the project.
+//
+// f must be locked.
+func (f *FakeData) put(secret client.BarbicanSecretWithPayload) {
+	id := client.RefID(secret.Secret.SecretRef)
+	f.data[id] = secret
+	if store, ok := f.currentSecretStore(); ok {
+		f.secretStores[id] = store.ID()
+	}
+}
+
+// remove deletes the secret with the given UUID
+// together with its metadata, consumers and ACL.
+//
+// f must be locked.
+func (f *FakeData) remove(id string) {
+	delete(f.data, id)
+	delete(f.metadata, id)
+	delete(f.consumers, id)
+	delete(f.acls, id)
+	delete(f.secretStores, id)
 }
 
 func (f *FakeData) List() []client.BarbicanSecret {
@@ -69,3 +233,429 @@ func (f *FakeData) List() []client.BarbicanSecret {
 	f.RUnlock()
 	return list
 }
+
+// GetMetadata returns a copy of the user metadata of the
+// secret addressed by ref. It returns ErrKeyNotFound if no
+// such secret exists.
+func (f *FakeData) GetMetadata(ref string) (map[string]string, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	id := client.RefID(ref)
+	if _, ok := f.data[id]; !ok {
+		return nil, xerror.ErrKeyNotFound
+	}
+	metadata := make(map[string]string, len(f.metadata[id]))
+	for k, v := range f.metadata[id] {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
+
+// UpdateMetadata applies fn to the user metadata of the
+// secret addressed by ref while holding the lock. It returns
+// ErrKeyNotFound if no such secret exists and any error
+// returned by fn.
+func (f *FakeData) UpdateMetadata(ref string, fn func(map[string]string) error) error {
+	f.Lock()
+	defer f.Unlock()
+
+	id := client.RefID(ref)
+	if _, ok := f.data[id]; !ok {
+		return xerror.ErrKeyNotFound
+	}
+	metadata, ok := f.metadata[id]
+	if !ok {
+		metadata = make(map[string]string)
+		f.metadata[id] = metadata
+	}
+	return fn(metadata)
+}
+
+// UpdateSecretConsumers applies fn to the consumers of the
+// secret addressed by ref while holding the lock. It returns
+// ErrKeyNotFound if no such secret exists and any error
+// returned by fn.
+func (f *FakeData) UpdateSecretConsumers(ref string, fn func([]client.SecretConsumer) ([]client.SecretConsumer, error)) error {
+	f.Lock()
+	defer f.Unlock()
+
+	id := client.RefID(ref)
+	if _, ok := f.data[id]; !ok {
+		return xerror.ErrKeyNotFound
+	}
+	consumers, err := fn(f.consumers[id])
+	if err != nil {
+		return err
+	}
+	f.consumers[id] = consumers
+	return nil
+}
+
+// GetSecretConsumers returns a copy of the consumers of the
+// secret addressed by ref. It returns ErrKeyNotFound if no
+// such secret exists.
+func (f *FakeData) GetSecretConsumers(ref string) ([]client.SecretConsumer, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	id := client.RefID(ref)
+	if _, ok := f.data[id]; !ok {
+		return nil, xerror.ErrKeyNotFound
+	}
+	return append([]client.SecretConsumer{}, f.consumers[id]...), nil
+}
+
+func (f *FakeData) SetContainer(container client.BarbicanContainer) {
+	f.Lock()
+	f.containers[client.RefID(container.ContainerRef)] = container
+	f.Unlock()
+}
+
+func (f *FakeData) GetContainer(ref string) (container client.BarbicanContainer, exist bool) {
+	f.RLock()
+	container, exist = f.containers[client.RefID(ref)]
+	f.RUnlock()
+	return
+}
+
+func (f *FakeData) DeleteContainer(ref string) (exist bool) {
+	f.Lock()
+	id := client.RefID(ref)
+	_, exist = f.containers[id]
+	delete(f.containers, id)
+	delete(f.acls, id)
+	f.Unlock()
+	return
+}
+
+// UpdateContainer applies fn to the container addressed by
+// ref while holding the lock. It returns ErrContainerNotFound
+// if no such container exists and any error returned by fn.
+func (f *FakeData) UpdateContainer(ref string, fn func(*client.BarbicanContainer) error) error {
+	f.Lock()
+	defer f.Unlock()
+
+	id := client.RefID(ref)
+	container, ok := f.containers[id]
+	if !ok {
+		return xerror.ErrContainerNotFound
+	}
+	if err := fn(&container); err != nil {
+		return err
+	}
+	container.Updated = newCreationTime()
+	f.containers[id] = container
+	return nil
+}
+
+func (f *FakeData) ListContainers() []client.BarbicanContainer {
+	f.RLock()
+	list := []client.BarbicanContainer{}
+	for _, container := range f.containers {
+		list = append(list, container)
+	}
+	f.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created != list[j].Created {
+			return list[i].Created > list[j].Created
+		}
+		return list[i].ContainerRef < list[j].ContainerRef
+	})
+	return list
+}
+
+func (f *FakeData) SetOrder(o *order) {
+	f.Lock()
+	f.orders[client.RefID(o.OrderRef)] = o
+	f.Unlock()
+}
+
+// PollOrder returns the order addressed by ref, if any,
+// and advances its lifecycle: once the order has been
+// reported as PENDING often enough, it is fulfilled.
+func (f *FakeData) PollOrder(ref string) (order client.BarbicanOrder, exist bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	o, exist := f.orders[client.RefID(ref)]
+	if !exist {
+		return
+	}
+	if o.Status == client.OrderStatusPending {
+		if o.pendingPolls > 0 {
+			o.pendingPolls--
+		} else {
+			f.fulfillOrder(o)
+		}
+	}
+	return o.BarbicanOrder, true
+}
+
+func (f *FakeData) DeleteOrder(ref string) (exist bool) {
+	f.Lock()
+	id := client.RefID(ref)
+	_, exist = f.orders[id]
+	delete(f.orders, id)
+	f.Unlock()
+	return
+}
+
+func (f *FakeData) ListOrders() []client.BarbicanOrder {
+	f.RLock()
+	list := []client.BarbicanOrder{}
+	for _, o := range f.orders {
+		list = append(list, o.BarbicanOrder)
+	}
+	f.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Created != list[j].Created {
+			return list[i].Created > list[j].Created
+		}
+		return list[i].OrderRef < list[j].OrderRef
+	})
+	return list
+}
+
+// GetACL returns the ACL of the secret or container
+// addressed by ref. Resources without an explicit ACL
+// grant project-wide access.
+func (f *FakeData) GetACL(ref string) (acl client.ACL, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	id := client.RefID(ref)
+	if !f.exists(id) {
+		return acl, false
+	}
+	acl, ok := f.acls[id]
+	if !ok {
+		projectAccess := true
+		acl.Read.ProjectAccess = &projectAccess
+	}
+	return acl, true
+}
+
+// UpdateACL applies fn to the ACL of the secret or container
+// addressed by ref while holding the lock. It returns false if
+// no such resource exists.
+func (f *FakeData) UpdateACL(ref string, fn func(client.ACL) client.ACL) (exist bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	id := client.RefID(ref)
+	if !f.exists(id) {
+		return false
+	}
+	acl, ok := f.acls[id]
+	acl = fn(acl)
+	if !ok {
+		acl.Read.Created = newCreationTime()
+	}
+	acl.Read.Updated = newCreationTime()
+	f.acls[id] = acl
+	return true
+}
+
+// DeleteACL resets the ACL of the secret or container
+// addressed by ref. It returns false if no such resource
+// exists.
+func (f *FakeData) DeleteACL(ref string) (exist bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	id := client.RefID(ref)
+	if !f.exists(id) {
+		return false
+	}
+	delete(f.acls, id)
+	return true
+}
+
+// CheckAccess returns ErrForbidden if the given user may not
+// read - or, if write is true, modify - the secret or container
+// addressed by ref. Everyone may read resources with project
+// access, otherwise only the creator and the users listed in the
+// ACL. Private resources may only be modified by their creator.
+//
+// CheckAccess returns nil for resources that do not exist.
+func (f *FakeData) CheckAccess(ref, user string, write bool) error {
+	f.RLock()
+	defer f.RUnlock()
+
+	id := client.RefID(ref)
+	var creator string
+	if secret, ok := f.data[id]; ok {
+		creator = secret.Secret.CreatorID
+	} else if container, ok := f.containers[id]; ok {
+		creator = container.CreatorID
+	} else {
+		return nil
+	}
+
+	acl, ok := f.acls[id]
+	if !ok || acl.Read.HasProjectAccess() || user == creator {
+		return nil
+	}
+	if !write && acl.Read.HasUser(user) {
+		return nil
+	}
+	return xerror.ErrForbidden
+}
+
+// exists reports whether a secret or container
+// with the given UUID exists.
+//
+// f must be locked.
+func (f *FakeData) exists(id string) bool {
+	if _, ok := f.data[id]; ok {
+		return true
+	}
+	_, ok := f.containers[id]
+	return ok
+}
+
+// newSecretStore returns a new ACTIVE secret store.
+func newSecretStore(name, storePlugin, cryptoPlugin string, globalDefault bool) client.SecretStore {
+	return client.SecretStore{
+		Created:           newCreationTime(),
+		CryptoPlugin:      cryptoPlugin,
+		GlobalDefault:     globalDefault,
+		Name:              name,
+		SecretStorePlugin: storePlugin,
+		SecretStoreRef:    newRef("secret-stores"),
+		Status:            "ACTIVE",
+		Updated:           newCreationTime(),
+	}
+}
+
+// SetSecretStores replaces the available secret stores. Stores
+// without a reference get one assigned. If no store is marked
+// as global default, the first one becomes the global default.
+// Existing secrets are moved to the global default store.
+func (f *FakeData) SetSecretStores(stores []client.SecretStore) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.stores = make([]client.SecretStore, 0, len(stores))
+	hasDefault := false
+	for _, store := range stores {
+		if store.SecretStoreRef == "" {
+			store.SecretStoreRef = newRef("secret-stores")
+		}
+		if store.Status == "" {
+			store.Status = "ACTIVE"
+		}
+		if store.Created == "" {
+			store.Created, store.Updated = newCreationTime(), newCreationTime()
+		}
+		store.GlobalDefault = store.GlobalDefault && !hasDefault
+		hasDefault = hasDefault || store.GlobalDefault
+		f.stores = append(f.stores, store)
+	}
+	if !hasDefault && len(f.stores) > 0 {
+		f.stores[0].GlobalDefault = true
+	}
+
+	f.preferredStore = ""
+	for id := range f.data {
+		if store, ok := f.currentSecretStore(); ok {
+			f.secretStores[id] = store.ID()
+		}
+	}
+}
+
+// ListSecretStores returns all secret stores.
+func (f *FakeData) ListSecretStores() []client.SecretStore {
+	f.RLock()
+	defer f.RUnlock()
+	return append([]client.SecretStore(nil), f.stores...)
+}
+
+// GetSecretStore returns the secret store addressed
+// by the given reference or UUID, if any.
+func (f *FakeData) GetSecretStore(ref string) (store client.SecretStore, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+	return f.secretStore(client.RefID(ref))
+}
+
+// PreferredSecretStore returns the preferred
+// secret store of the project, if any.
+func (f *FakeData) PreferredSecretStore() (store client.SecretStore, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+	if f.preferredStore == "" {
+		return store, false
+	}
+	return f.secretStore(f.preferredStore)
+}
+
+// SetPreferredSecretStore makes the secret store addressed by
+// ref the preferred one. It returns false if no such store exists.
+func (f *FakeData) SetPreferredSecretStore(ref string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	store, ok := f.secretStore(client.RefID(ref))
+	if !ok {
+		return false
+	}
+	f.preferredStore = store.ID()
+	return true
+}
+
+// UnsetPreferredSecretStore removes the preferred secret store
+// if it is the one addressed by ref. Otherwise, it returns false.
+func (f *FakeData) UnsetPreferredSecretStore(ref string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.preferredStore == "" || f.preferredStore != client.RefID(ref) {
+		return false
+	}
+	f.preferredStore = ""
+	return true
+}
+
+// SecretStoreOf returns the secret store holding the secret
+// addressed by the given reference or UUID, if any.
+func (f *FakeData) SecretStoreOf(ref string) (store client.SecretStore, exist bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	id, ok := f.secretStores[client.RefID(ref)]
+	if !ok {
+		return store, false
+	}
+	return f.secretStore(id)
+}
+
+// currentSecretStore returns the secret store new secrets
+// are stored in - i.e. the preferred secret store of the
+// project or, if not set, the global default.
+//
+// f must be locked.
+func (f *FakeData) currentSecretStore() (client.SecretStore, bool) {
+	if f.preferredStore != "" {
+		return f.secretStore(f.preferredStore)
+	}
+	for _, store := range f.stores {
+		if store.GlobalDefault {
+			return store, true
+		}
+	}
+	return client.SecretStore{}, false
+}
+
+// secretStore returns the secret store with the given UUID.
+//
+// f must be locked.
+func (f *FakeData) secretStore(id string) (client.SecretStore, bool) {
+	for _, store := range f.stores {
+		if store.ID() == id {
+			return store, true
+		}
+	}
+	return client.SecretStore{}, false
+}
@@ -0,0 +1,127 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// filterSecrets returns the secrets matching the given filter,
+// ordered by its sort keys - like Barbican does. Secrets are
+// ordered by creation time unless specified otherwise. Ties
+// are broken by the secret UUID.
+func (c *Client) filterSecrets(secrets []client.BarbicanSecret, filter client.SecretFilter) ([]client.BarbicanSecret, error) {
+	for _, key := range filter.Sort {
+		if _, err := sortValue(client.BarbicanSecret{}, key.Key); err != nil {
+			return nil, err
+		}
+		if key.Direction != "" && key.Direction != client.SortAsc && key.Direction != client.SortDesc {
+			return nil, xerror.NewError(http.StatusBadRequest, fmt.Sprintf("invalid sort direction '%s'", key.Direction))
+		}
+	}
+
+	matches := make([]client.BarbicanSecret, 0, len(secrets))
+	for _, s := range secrets {
+		if filter.Name != "" && s.Name != filter.Name {
+			continue
+		}
+		if filter.Algorithm != "" && !strings.EqualFold(stringValue(s.Algorithm), filter.Algorithm) {
+			continue
+		}
+		if filter.Mode != "" && !strings.EqualFold(stringValue(s.Mode), filter.Mode) {
+			continue
+		}
+		if filter.BitLength != 0 && stringValue(s.BitLength) != fmt.Sprint(filter.BitLength) {
+			continue
+		}
+		if filter.SecretType != "" && s.SecretType != string(filter.SecretType) {
+			continue
+		}
+		if filter.ACLOnly {
+			if acl, ok := c.fakeData.GetACL(s.SecretRef); !ok || !acl.Read.HasUser(c.user) {
+				continue
+			}
+		}
+		if !inRange(s.Created, filter.Created) || !inRange(s.Updated, filter.Updated) || !inRange(stringValue(s.Expiration), filter.Expiration) {
+			continue
+		}
+		matches = append(matches, s)
+	}
+
+	keys := make([]client.SortKey, 0, len(filter.Sort)+1)
+	keys = append(keys, filter.Sort...)
+	keys = append(keys, client.SortKey{Key: client.SortByCreated})
+	sort.SliceStable(matches, func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := sortValue(matches[i], key.Key)
+			b, _ := sortValue(matches[j], key.Key)
+			if a == b {
+				continue
+			}
+			if key.Direction == client.SortDesc {
+				return a > b
+			}
+			return a < b
+		}
+		return matches[i].ID() < matches[j].ID()
+	})
+	return matches, nil
+}
+
+// sortValue returns the value of the given sort key such
+// that string comparison orders secrets like Barbican.
+func sortValue(s client.BarbicanSecret, key string) (string, error) {
+	switch key {
+	case client.SortByCreated:
+		return s.Created, nil
+	case client.SortByUpdated:
+		return s.Updated, nil
+	case client.SortByExpiration:
+		return stringValue(s.Expiration), nil
+	case client.SortByName:
+		return s.Name, nil
+	case client.SortByAlgorithm:
+		return stringValue(s.Algorithm), nil
+	case client.SortByBitLength:
+		// Pad such that bit lengths are ordered numerically.
+		if n, err := strconv.Atoi(stringValue(s.BitLength)); err == nil {
+			return fmt.Sprintf("%010d", n), nil
+		}
+		return "", nil
+	case client.SortByMode:
+		return stringValue(s.Mode), nil
+	case client.SortBySecretType:
+		return s.SecretType, nil
+	case client.SortByStatus:
+		return s.Status, nil
+	default:
+		return "", xerror.NewError(http.StatusBadRequest, fmt.Sprintf("invalid sort key '%s'", key))
+	}
+}
+
+// stringValue returns the string representation of an
+// optional secret attribute, or "" if it is not set.
+func stringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// inRange reports whether the given timestamp lies within r.
+// Unset timestamps only match ranges without bounds.
+func inRange(timestamp string, r client.TimeRange) bool {
+	if r.IsZero() {
+		return true
+	}
+	t, err := client.ParseTime(timestamp)
+	if err != nil {
+		return false
+	}
+	return r.Contains(t)
+}
@@ -0,0 +1,231 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+)
+
+// certificateValidity is the validity period of
+// certificates issued by the fake CA.
+const certificateValidity = 365 * 24 * time.Hour
+
+// caPluginName is the certificate plugin of the fake CAs.
+const caPluginName = "fake_ca"
+
+// fakeCA is a certificate authority issuing the
+// certificates of fake certificate orders.
+type fakeCA struct {
+	id      string
+	name    string
+	created string
+
+	key  crypto.Signer
+	cert *x509.Certificate
+	pem  []byte
+}
+
+func newFakeCA(name string) (*fakeCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          newSerialNumber(),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(10 * certificateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeCA{
+		id:      newUUID(),
+		name:    name,
+		created: newCreationTime(),
+		key:     key,
+		cert:    cert,
+		pem:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+// info returns the CA as reported by Barbican.
+func (ca *fakeCA) info() client.CA {
+	return client.CA{
+		CAID:       ca.id,
+		Created:    ca.created,
+		Expiration: ca.cert.NotAfter.UTC().Format(timeFormat),
+		Meta: []map[string]string{
+			{"name": ca.name},
+			{"description": ca.name},
+			{"ca_signing_certificate": string(ca.pem)},
+			{"intermediates": ""},
+		},
+		PluginCAID: ca.id,
+		PluginName: caPluginName,
+		Status:     "ACTIVE",
+		Updated:    ca.created,
+	}
+}
+
+// issue returns a PEM encoded certificate for the given
+// subject and public key signed by the CA.
+func (ca *fakeCA) issue(subject pkix.Name, publicKey crypto.PublicKey) ([]byte, error) {
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if subject.CommonName != "" {
+		template.DNSNames = []string{subject.CommonName}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, publicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func newSerialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(err)
+	}
+	return serial
+}
+
+// generateKey returns a new random symmetric key
+// for the algorithm and bit length of a key order.
+func generateKey(meta client.OrderMeta) ([]byte, error) {
+	switch algorithm := strings.ToLower(meta.Algorithm); {
+	case algorithm == "aes":
+		if meta.BitLength != 128 && meta.BitLength != 192 && meta.BitLength != 256 {
+			return nil, fmt.Errorf("invalid bit_length %d for algorithm 'aes'", meta.BitLength)
+		}
+	case strings.HasPrefix(algorithm, "hmacsha"):
+		if meta.BitLength <= 0 || meta.BitLength%8 != 0 {
+			return nil, fmt.Errorf("invalid bit_length %d for algorithm '%s'", meta.BitLength, algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm '%s'", meta.Algorithm)
+	}
+
+	key := make([]byte, meta.BitLength/8)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// generateKeyPair returns a new PEM encoded key pair
+// for the algorithm and bit length of an asymmetric order.
+func generateKeyPair(meta client.OrderMeta) (privateKey, publicKey []byte, err error) {
+	if !strings.EqualFold(meta.Algorithm, "rsa") {
+		return nil, nil, fmt.Errorf("unsupported algorithm '%s'", meta.Algorithm)
+	}
+	switch meta.BitLength {
+	case 1024, 2048, 3072, 4096:
+	default:
+		return nil, nil, fmt.Errorf("invalid bit_length %d for algorithm 'rsa'", meta.BitLength)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, meta.BitLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	public, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}), nil
+}
+
+// parsePublicKey parses a PEM or DER encoded PKIX public key.
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	return x509.ParsePKIXPublicKey(data)
+}
+
+// parseCertificateRequest parses the base64 encoded
+// PEM or DER PKCS#10 request of a simple-cmc order.
+func parseCertificateRequest(requestData string) (*x509.CertificateRequest, error) {
+	data, err := base64.StdEncoding.DecodeString(requestData)
+	if err != nil {
+		return nil, errors.New("request_data is not base64 encoded")
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	csr, err := x509.ParseCertificateRequest(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate request: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %v", err)
+	}
+	return csr, nil
+}
+
+// parseSubjectDN parses a distinguished name like
+// "CN=example.com,O=Example,C=US".
+func parseSubjectDN(dn string) (pkix.Name, error) {
+	var name pkix.Name
+	if strings.TrimSpace(dn) == "" {
+		return name, errors.New("subject_dn must not be empty")
+	}
+	for _, rdn := range strings.Split(dn, ",") {
+		key, value, ok := strings.Cut(rdn, "=")
+		if !ok {
+			return name, fmt.Errorf("invalid subject_dn '%s'", dn)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "CN":
+			name.CommonName = value
+		case "O":
+			name.Organization = append(name.Organization, value)
+		case "OU":
+			name.OrganizationalUnit = append(name.OrganizationalUnit, value)
+		case "C":
+			name.Country = append(name.Country, value)
+		case "ST":
+			name.Province = append(name.Province, value)
+		case "L":
+			name.Locality = append(name.Locality, value)
+		default:
+			return name, fmt.Errorf("unsupported attribute '%s' in subject_dn", key)
+		}
+	}
+	return name, nil
+}
@@ -1,6 +1,10 @@
 package fake
 
-import "context"
+import (
+	"context"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+)
 
 type Iterator struct {
 	ch     <-chan string
@@ -29,3 +33,90 @@ func (i *Iterator) Close() error {
 	// i.cancel(context.Canceled)
 	return context.Cause(i.ctx)
 }
+
+type SecretIterator struct {
+	ch     <-chan client.BarbicanSecret
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next secret, if any.
+//
+// It returns true if and only if there is a new secret
+// available. If there are no more secrets or an error
+// has been encountered, Next returns false.
+func (i *SecretIterator) Next() (*client.BarbicanSecret, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of secrets.
+func (i *SecretIterator) Close() error {
+	return context.Cause(i.ctx)
+}
+
+type ContainerIterator struct {
+	ch     <-chan client.BarbicanContainer
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next container, if any.
+//
+// It returns true if and only if there is a new container
+// available. If there are no more containers or an error
+// has been encountered, Next returns false.
+func (i *ContainerIterator) Next() (*client.BarbicanContainer, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of containers.
+func (i *ContainerIterator) Close() error {
+	return context.Cause(i.ctx)
+}
+
+type OrderIterator struct {
+	ch     <-chan client.BarbicanOrder
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Next moves the iterator to the next order, if any.
+//
+// It returns true if and only if there is a new order
+// available. If there are no more orders or an error
+// has been encountered, Next returns false.
+func (i *OrderIterator) Next() (*client.BarbicanOrder, bool) {
+	select {
+	case v, ok := <-i.ch:
+		if !ok {
+			return nil, false
+		}
+		return &v, true
+	case <-i.ctx.Done():
+		return nil, false
+	}
+}
+
+// Close returns the first error, if any, encountered
+// while iterating over the set of orders.
+func (i *OrderIterator) Close() error {
+	return context.Cause(i.ctx)
+}
@@ -0,0 +1,104 @@
+package fake
+
+import (
+	"context"
+	"strings"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func (c *Client) GetSecretWithMetadata(ctx context.Context, id string) (*client.BarbicanSecret, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	secret, err := c.GetSecretByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Metadata, err = c.fakeData.GetMetadata(id); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (c *Client) GetSecretMetadata(ctx context.Context, id string) (map[string]string, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return nil, err
+	}
+	return c.fakeData.GetMetadata(id)
+}
+
+// PutSecretMetadata replaces the user metadata of the secret.
+// Like Barbican, the fake stores keys in lower case.
+func (c *Client) PutSecretMetadata(ctx context.Context, id string, metadata map[string]string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateMetadata(id, func(m map[string]string) error {
+		for k := range m {
+			delete(m, k)
+		}
+		for k, v := range metadata {
+			m[strings.ToLower(k)] = v
+		}
+		return nil
+	})
+}
+
+func (c *Client) AddSecretMetadatum(ctx context.Context, id, key, value string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateMetadata(id, func(m map[string]string) error {
+		key = strings.ToLower(key)
+		if _, ok := m[key]; ok {
+			return xerror.ErrMetadatumExists
+		}
+		m[key] = value
+		return nil
+	})
+}
+
+func (c *Client) UpdateSecretMetadatum(ctx context.Context, id, key, value string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateMetadata(id, func(m map[string]string) error {
+		key = strings.ToLower(key)
+		if _, ok := m[key]; !ok {
+			return xerror.ErrMetadatumNotFound
+		}
+		m[key] = value
+		return nil
+	})
+}
+
+func (c *Client) DeleteSecretMetadatum(ctx context.Context, id, key string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, true); err != nil {
+		return err
+	}
+	return c.fakeData.UpdateMetadata(id, func(m map[string]string) error {
+		key = strings.ToLower(key)
+		if _, ok := m[key]; !ok {
+			return xerror.ErrMetadatumNotFound
+		}
+		delete(m, key)
+		return nil
+	})
+}
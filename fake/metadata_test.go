@@ -0,0 +1,65 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestSecretMetadata(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	secret, err := conn.Create(ctx, "my-key", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	id := secret.ID()
+
+	if err = conn.PutSecretMetadata(ctx, id, map[string]string{"Purpose": "test", "owner": "alice"}); err != nil {
+		t.Fatalf("Failed to put metadata: %v", err)
+	}
+	if err = conn.AddSecretMetadatum(ctx, id, "PURPOSE", "prod"); !errors.Is(err, xerror.ErrMetadatumExists) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrMetadatumExists)
+	}
+	if err = conn.AddSecretMetadatum(ctx, id, "team", "security"); err != nil {
+		t.Fatalf("Failed to add metadatum: %v", err)
+	}
+	if err = conn.UpdateSecretMetadatum(ctx, id, "Purpose", "prod"); err != nil {
+		t.Fatalf("Failed to update metadatum: %v", err)
+	}
+	if err = conn.DeleteSecretMetadatum(ctx, id, "owner"); err != nil {
+		t.Fatalf("Failed to delete metadatum: %v", err)
+	}
+
+	want := map[string]string{"purpose": "prod", "team": "security"}
+	withMetadata, err := conn.GetSecretWithMetadata(ctx, id)
+	if err != nil {
+		t.Fatalf("Failed to fetch secret: %v", err)
+	}
+	if !reflect.DeepEqual(withMetadata.Metadata, want) {
+		t.Fatalf("Got metadata %v - want %v", withMetadata.Metadata, want)
+	}
+
+	if err = conn.SetSecretACL(ctx, id, client.PrivateACL("bob")); err != nil {
+		t.Fatalf("Failed to set ACL: %v", err)
+	}
+	for i, test := range []struct {
+		Err  error
+		Want error
+	}{
+		{Err: conn.UpdateSecretMetadatum(ctx, id, "owner", "bob"), Want: xerror.ErrMetadatumNotFound},   // 0
+		{Err: conn.DeleteSecretMetadatum(ctx, id, "owner"), Want: xerror.ErrMetadatumNotFound},          // 1
+		{Err: conn.AddSecretMetadatum(ctx, "missing", "owner", "bob"), Want: xerror.ErrKeyNotFound},     // 2
+		{Err: conn.PutSecretMetadata(ctx, "missing", map[string]string{}), Want: xerror.ErrKeyNotFound}, // 3
+		{Err: conn.AsUser("bob").AddSecretMetadatum(ctx, id, "owner", "bob"), Want: xerror.Forbidden},   // 4 - Only the creator may modify private secrets
+	} {
+		if !errors.Is(test.Err, test.Want) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, test.Err, test.Want)
+		}
+	}
+}
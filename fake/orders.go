@@ -0,0 +1,338 @@
+package fake
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// orderPendingPolls is the number of times a new order
+// is reported as PENDING before it is processed.
+const orderPendingPolls = 1
+
+// SubmitOrder stores a new PENDING order. The order is
+// processed once it has been polled orderPendingPolls times.
+func (c *Client) SubmitOrder(ctx context.Context, spec client.OrderSpec) (*client.BarbicanOrder, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	request := spec.CreateRequest()
+	switch request.Type {
+	case client.OrderTypeKey, client.OrderTypeAsymmetric, client.OrderTypeCertificate:
+	default:
+		return nil, xerror.NewError(http.StatusBadRequest, fmt.Sprintf("couldn't create. Provided object does not match schema 'Order': '%s' is not one of ['key', 'asymmetric', 'certificate']", request.Type))
+	}
+	if err := c.fakeData.CheckQuota(quotaOrders); err != nil {
+		return nil, err
+	}
+
+	o := &order{
+		BarbicanOrder: client.BarbicanOrder{
+			Created:   newCreationTime(),
+			CreatorID: c.user,
+			Meta:      request.Meta,
+			OrderRef:  newRef("orders"),
+			Status:    client.OrderStatusPending,
+			Type:      request.Type,
+			Updated:   newCreationTime(),
+		},
+		pendingPolls: orderPendingPolls,
+	}
+	c.fakeData.SetOrder(o)
+	result := o.BarbicanOrder
+	return &result, nil
+}
+
+func (c *Client) GetOrder(ctx context.Context, ref string) (*client.BarbicanOrder, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	o, ok := c.fakeData.PollOrder(ref)
+	if !ok {
+		return nil, xerror.ErrOrderNotFound
+	}
+	return &o, nil
+}
+
+// WaitForOrder polls the order addressed by ref every interval
+// until it is either ACTIVE or ERROR, or until ctx is done.
+func (c *Client) WaitForOrder(ctx context.Context, ref string, interval time.Duration) (*client.BarbicanOrder, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		o, err := c.GetOrder(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		switch o.Status {
+		case client.OrderStatusActive:
+			return o, nil
+		case client.OrderStatusError:
+			return o, o.Err()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+}
+
+func (c *Client) DeleteOrder(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.DeleteOrder(ref) {
+		return xerror.ErrOrderNotFound
+	}
+	return nil
+}
+
+// ListOrders returns a new OrderIterator over all
+// fake orders, newest first.
+func (c *Client) ListOrders(ctx context.Context) (client.OrderIterator, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan client.BarbicanOrder, 10)
+
+	go func() {
+		defer close(values)
+		for _, o := range c.fakeData.ListOrders() {
+			select {
+			case values <- o:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &OrderIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// fulfillOrder generates the secret or container requested by
+// the given order and marks the order ACTIVE. If the order cannot
+// be fulfilled, e.g. because the project's quota is exceeded, any
+// secrets generated so far are removed and the order is marked
+// ERROR instead.
+//
+// f must be locked.
+func (f *FakeData) fulfillOrder(o *order) {
+	var err error
+	switch o.Type {
+	case client.OrderTypeKey:
+		err = f.fulfillKeyOrder(o)
+	case client.OrderTypeAsymmetric:
+		err = f.fulfillAsymmetricOrder(o)
+	case client.OrderTypeCertificate:
+		err = f.fulfillCertificateOrder(o)
+	}
+
+	o.Updated = newCreationTime()
+	if err != nil {
+		for _, id := range o.generated {
+			f.remove(id)
+		}
+		o.SecretRef, o.ContainerRef, o.generated = "", "", nil
+
+		status, reason := http.StatusBadRequest, err.Error()
+		var e xerror.Error
+		if errors.As(err, &e) {
+			status, reason = e.Status(), e.Message()
+		}
+		o.Status = client.OrderStatusError
+		o.ErrorStatusCode = strconv.Itoa(status)
+		o.ErrorReason = reason
+		return
+	}
+	o.Status = client.OrderStatusActive
+}
+
+// f must be locked.
+func (f *FakeData) fulfillKeyOrder(o *order) error {
+	key, err := generateKey(o.Meta)
+	if err != nil {
+		return err
+	}
+	secret, err := f.addGeneratedSecret(o, o.Meta, "symmetric", key)
+	if err != nil {
+		return err
+	}
+	o.SecretRef = secret.SecretRef
+	return nil
+}
+
+// f must be locked.
+func (f *FakeData) fulfillAsymmetricOrder(o *order) error {
+	privateKey, publicKey, err := generateKeyPair(o.Meta)
+	if err != nil {
+		return err
+	}
+
+	private, err := f.addGeneratedSecret(o, o.Meta, "private", privateKey)
+	if err != nil {
+		return err
+	}
+	public, err := f.addGeneratedSecret(o, o.Meta, "public", publicKey)
+	if err != nil {
+		return err
+	}
+	refs := []client.ContainerSecretRef{
+		{Name: client.ContainerSecretPrivateKey, SecretRef: private.SecretRef},
+		{Name: client.ContainerSecretPublicKey, SecretRef: public.SecretRef},
+	}
+	if o.Meta.PassPhrase != "" {
+		passphrase, err := f.addGeneratedSecret(o, client.OrderMeta{PayloadContentType: "text/plain"}, "passphrase", []byte(o.Meta.PassPhrase))
+		if err != nil {
+			return err
+		}
+		refs = append(refs, client.ContainerSecretRef{Name: client.ContainerSecretPrivateKeyPassphrase, SecretRef: passphrase.SecretRef})
+	}
+	container, err := f.addGeneratedContainer(o, client.ContainerTypeRSA, refs)
+	if err != nil {
+		return err
+	}
+	o.ContainerRef = container.ContainerRef
+	return nil
+}
+
+// f must be locked.
+func (f *FakeData) fulfillCertificateOrder(o *order) error {
+	var (
+		subject   pkix.Name
+		publicKey crypto.PublicKey
+		refs      []client.ContainerSecretRef
+		err       error
+	)
+	switch o.Meta.RequestType {
+	case client.CertificateRequestSimpleCMC:
+		csr, err := parseCertificateRequest(o.Meta.RequestData)
+		if err != nil {
+			return err
+		}
+		subject, publicKey = csr.Subject, csr.PublicKey
+	case client.CertificateRequestStoredKey:
+		if subject, err = parseSubjectDN(o.Meta.SubjectDN); err != nil {
+			return err
+		}
+		source, ok := f.containers[client.RefID(o.Meta.SourceContainerRef)]
+		if !ok || source.Type != client.ContainerTypeRSA {
+			return errors.New("container_ref must reference an existing rsa container")
+		}
+		publicKeyRef, _ := source.SecretRef(client.ContainerSecretPublicKey)
+		publicKeySecret, ok := f.data[client.RefID(publicKeyRef)]
+		if !ok {
+			return errors.New("public key of the rsa container does not exist")
+		}
+		if publicKey, err = parsePublicKey(publicKeySecret.Payload); err != nil {
+			return fmt.Errorf("invalid public key: %v", err)
+		}
+		for _, ref := range source.SecretRefs {
+			if ref.Name == client.ContainerSecretPrivateKey || ref.Name == client.ContainerSecretPrivateKeyPassphrase {
+				refs = append(refs, ref)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported request_type '%s'", o.Meta.RequestType)
+	}
+
+	ca, err := f.issuingCA(client.RefID(o.Meta.CAID))
+	if err != nil {
+		return err
+	}
+	cert, err := ca.issue(subject, publicKey)
+	if err != nil {
+		return err
+	}
+
+	meta := client.OrderMeta{PayloadContentType: "application/octet-stream"}
+	certificate, err := f.addGeneratedSecret(o, meta, "certificate", cert)
+	if err != nil {
+		return err
+	}
+	intermediates, err := f.addGeneratedSecret(o, meta, "certificate", ca.pem)
+	if err != nil {
+		return err
+	}
+	refs = append(refs,
+		client.ContainerSecretRef{Name: client.ContainerSecretCertificate, SecretRef: certificate.SecretRef},
+		client.ContainerSecretRef{Name: client.ContainerSecretIntermediates, SecretRef: intermediates.SecretRef},
+	)
+	container, err := f.addGeneratedContainer(o, client.ContainerTypeCertificate, refs)
+	if err != nil {
+		return err
+	}
+	o.ContainerRef = container.ContainerRef
+	return nil
+}
+
+// addGeneratedSecret stores a new secret generated by an order.
+// It fails if the project's secret quota is exceeded.
+//
+// f must be locked.
+func (f *FakeData) addGeneratedSecret(o *order, meta client.OrderMeta, secretType string, payload []byte) (client.BarbicanSecret, error) {
+	if err := f.checkQuota(quotaSecrets); err != nil {
+		return client.BarbicanSecret{}, err
+	}
+	contentType := meta.PayloadContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	secret := newSecret(client.SecretCreateRequest{
+		Name:               o.Meta.Name,
+		Expiration:         meta.Expiration,
+		Algorithm:          meta.Algorithm,
+		BitLength:          meta.BitLength,
+		Mode:               meta.Mode,
+		PayloadContentType: contentType,
+		SecretType:         secretType,
+	}, payload)
+	secret.Secret.CreatorID = o.CreatorID
+	f.put(secret)
+	o.generated = append(o.generated, client.RefID(secret.Secret.SecretRef))
+	return secret.Secret, nil
+}
+
+// addGeneratedContainer stores a new container generated by an order.
+// It fails if the project's container quota is exceeded.
+//
+// f must be locked.
+func (f *FakeData) addGeneratedContainer(o *order, containerType client.ContainerType, refs []client.ContainerSecretRef) (client.BarbicanContainer, error) {
+	if err := f.checkQuota(quotaContainers); err != nil {
+		return client.BarbicanContainer{}, err
+	}
+	container := client.BarbicanContainer{
+		Consumers:    []client.ContainerConsumer{},
+		ContainerRef: newRef("containers"),
+		Created:      newCreationTime(),
+		CreatorID:    o.CreatorID,
+		Name:         o.Meta.Name,
+		SecretRefs:   refs,
+		Status:       "ACTIVE",
+		Type:         containerType,
+		Updated:      newCreationTime(),
+	}
+	f.containers[client.RefID(container.ContainerRef)] = container
+	return container, nil
+}
@@ -0,0 +1,90 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestOrders(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	for i, test := range orderTests {
+		order, err := conn.SubmitOrder(ctx, test.Spec)
+		if err != nil {
+			t.Fatalf("Test %d: failed to submit order: %v", i, err)
+		}
+		if order.Status != client.OrderStatusPending {
+			t.Fatalf("Test %d: got status '%s' - want '%s'", i, order.Status, client.OrderStatusPending)
+		}
+
+		order, err = conn.WaitForOrder(ctx, order.OrderRef, time.Millisecond)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but order %+v succeeded", i, *order)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: order failed: %v", i, err)
+		}
+		if test.ShouldFail {
+			if order == nil || order.Status != client.OrderStatusError || order.ResultRef() != "" {
+				t.Fatalf("Test %d: got order %v - want a failed order without result", i, order)
+			}
+			continue
+		}
+
+		switch test.Spec.CreateRequest().Type {
+		case client.OrderTypeKey:
+			payload, err := conn.GetPayloadByID(ctx, order.SecretRef)
+			if err != nil {
+				t.Fatalf("Test %d: failed to fetch generated key: %v", i, err)
+			}
+			if len(payload)*8 != test.Spec.CreateRequest().Meta.BitLength {
+				t.Fatalf("Test %d: got a %d bit key - want %d bits", i, len(payload)*8, test.Spec.CreateRequest().Meta.BitLength)
+			}
+		default:
+			container, err := conn.GetContainer(ctx, order.ContainerRef)
+			if err != nil {
+				t.Fatalf("Test %d: failed to fetch generated container: %v", i, err)
+			}
+			if _, err = container.RSA(); err != nil {
+				t.Fatalf("Test %d: generated container is not an rsa container: %v", i, err)
+			}
+		}
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	order, err := conn.SubmitOrder(ctx, client.KeyOrder{Algorithm: "aes", BitLength: 256})
+	if err != nil {
+		t.Fatalf("Failed to submit order: %v", err)
+	}
+	if err = conn.DeleteOrder(ctx, order.OrderRef); err != nil {
+		t.Fatalf("Failed to delete order: %v", err)
+	}
+	if err = conn.DeleteOrder(ctx, order.OrderRef); !errors.Is(err, xerror.ErrOrderNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrOrderNotFound)
+	}
+	if _, err = conn.GetOrder(ctx, order.OrderRef); !errors.Is(err, xerror.ErrOrderNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrOrderNotFound)
+	}
+}
+
+var orderTests = []struct {
+	Spec       client.OrderSpec
+	ShouldFail bool
+}{
+	{Spec: client.KeyOrder{Name: "my-key", Algorithm: "aes", BitLength: 256, Mode: "cbc"}},           // 0
+	{Spec: client.KeyOrder{Name: "my-key", Algorithm: "aes", BitLength: 100}, ShouldFail: true},      // 1
+	{Spec: client.AsymmetricOrder{Name: "my-key-pair", Algorithm: "rsa", BitLength: 2048}},           // 2
+	{Spec: client.AsymmetricOrder{Algorithm: "rsa", BitLength: 2048, PassPhrase: "secret"}},          // 3
+	{Spec: client.AsymmetricOrder{Algorithm: "dsa", BitLength: 2048}, ShouldFail: true},              // 4
+	{Spec: client.CertificateOrder{RequestType: client.CertificateRequestFullCMC}, ShouldFail: true}, // 5
+}
@@ -0,0 +1,162 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// Resources limited by quotas.
+const (
+	quotaSecrets    = "secrets"
+	quotaOrders     = "orders"
+	quotaContainers = "containers"
+	quotaConsumers  = "consumers"
+	quotaCAs        = "cas"
+)
+
+func (c *Client) GetQuotas(ctx context.Context) (*client.Quotas, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	quotas := c.fakeData.Quotas(DefaultProject)
+	return &quotas, nil
+}
+
+func (c *Client) ListProjectQuotas(ctx context.Context) ([]client.ProjectQuotasEntry, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	return c.fakeData.ListProjectQuotas(), nil
+}
+
+func (c *Client) GetProjectQuotas(ctx context.Context, projectID string) (*client.ProjectQuotas, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	quotas, ok := c.fakeData.GetProjectQuotas(projectID)
+	if !ok {
+		return nil, xerror.ErrProjectQuotasNotFound
+	}
+	return &quotas, nil
+}
+
+func (c *Client) SetProjectQuotas(ctx context.Context, projectID string, quotas client.ProjectQuotas) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	c.fakeData.SetProjectQuotas(projectID, quotas)
+	return nil
+}
+
+func (c *Client) DeleteProjectQuotas(ctx context.Context, projectID string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.DeleteProjectQuotas(projectID) {
+		return xerror.ErrProjectQuotasNotFound
+	}
+	return nil
+}
+
+// SetQuotas sets the default quotas.
+func (f *FakeData) SetQuotas(quotas client.Quotas) {
+	f.Lock()
+	f.quotas = quotas
+	f.Unlock()
+}
+
+// Quotas returns the effective quotas of the given project.
+func (f *FakeData) Quotas(projectID string) client.Quotas {
+	f.RLock()
+	defer f.RUnlock()
+	return f.projectQuotas[projectID].Apply(f.quotas)
+}
+
+// ListProjectQuotas returns the quotas of all projects
+// with project-specific quotas, ordered by project ID.
+func (f *FakeData) ListProjectQuotas() []client.ProjectQuotasEntry {
+	f.RLock()
+	list := make([]client.ProjectQuotasEntry, 0, len(f.projectQuotas))
+	for project, quotas := range f.projectQuotas {
+		list = append(list, client.ProjectQuotasEntry{ProjectID: project, ProjectQuotas: quotas})
+	}
+	f.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ProjectID < list[j].ProjectID })
+	return list
+}
+
+// GetProjectQuotas returns the quotas of
+// the given project, if configured.
+func (f *FakeData) GetProjectQuotas(projectID string) (quotas client.ProjectQuotas, exist bool) {
+	f.RLock()
+	quotas, exist = f.projectQuotas[projectID]
+	f.RUnlock()
+	return
+}
+
+// SetProjectQuotas replaces the quotas of the given project.
+func (f *FakeData) SetProjectQuotas(projectID string, quotas client.ProjectQuotas) {
+	f.Lock()
+	f.projectQuotas[projectID] = quotas
+	f.Unlock()
+}
+
+// DeleteProjectQuotas removes the quotas of the given
+// project. It returns false if none were configured.
+func (f *FakeData) DeleteProjectQuotas(projectID string) (exist bool) {
+	f.Lock()
+	_, exist = f.projectQuotas[projectID]
+	delete(f.projectQuotas, projectID)
+	f.Unlock()
+	return
+}
+
+// CheckQuota returns a quota-exceeded error if DefaultProject
+// may not create another resource of the given kind, e.g.
+// "secrets".
+func (f *FakeData) CheckQuota(resource string) error {
+	f.RLock()
+	defer f.RUnlock()
+	return f.checkQuota(resource)
+}
+
+// checkQuota is like CheckQuota.
+//
+// f must be locked.
+func (f *FakeData) checkQuota(resource string) error {
+	quotas := f.projectQuotas[DefaultProject].Apply(f.quotas)
+
+	var limit, count int
+	switch resource {
+	case quotaSecrets:
+		limit, count = quotas.Secrets, len(f.data)
+	case quotaOrders:
+		limit, count = quotas.Orders, len(f.orders)
+	case quotaContainers:
+		limit, count = quotas.Containers, len(f.containers)
+	case quotaConsumers:
+		limit = quotas.Consumers
+		for _, consumers := range f.consumers {
+			count += len(consumers)
+		}
+		for _, container := range f.containers {
+			count += len(container.Consumers)
+		}
+	case quotaCAs:
+		// Barbican only counts subordinate CAs created by the
+		// project, which the fake does not support.
+		limit, count = quotas.CAs, 0
+	default:
+		return nil
+	}
+	if limit < 0 || count < limit {
+		return nil
+	}
+	// Barbican reports exceeded quotas as 403 Forbidden.
+	return xerror.NewFault(http.StatusForbidden, "Forbidden", fmt.Sprintf("Quota reached for project %s. Only %d %s are allowed.", DefaultProject, limit, resource))
+}
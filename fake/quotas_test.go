@@ -0,0 +1,85 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestQuotas(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil, WithQuotas(client.Quotas{
+		Secrets:    2,
+		Orders:     client.UnlimitedQuota,
+		Containers: 1,
+		Consumers:  client.UnlimitedQuota,
+		CAs:        client.UnlimitedQuota,
+	}))
+
+	quotas, err := conn.GetQuotas(ctx)
+	if err != nil {
+		t.Fatalf("Failed to fetch quotas: %v", err)
+	}
+	if quotas.Secrets != 2 || quotas.Containers != 1 {
+		t.Fatalf("Got quotas %+v - want the configured quotas", *quotas)
+	}
+
+	if _, err = conn.Create(ctx, "my-key", []byte("Hello World")); err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	if _, err = conn.CreateContainer(ctx, client.GenericContainer{Name: "my-container"}); err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+	if _, err = conn.CreateContainer(ctx, client.GenericContainer{Name: "my-container-2"}); !errors.Is(err, xerror.QuotaExceeded) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.QuotaExceeded)
+	}
+
+	// An asymmetric order generates two secrets
+	// but only one more secret is allowed.
+	order, err := conn.SubmitOrder(ctx, client.AsymmetricOrder{Algorithm: "rsa", BitLength: 2048})
+	if err != nil {
+		t.Fatalf("Failed to submit order: %v", err)
+	}
+	order, err = conn.WaitForOrder(ctx, order.OrderRef, time.Millisecond)
+	if err == nil {
+		t.Fatalf("Order %+v succeeded beyond the secret quota", *order)
+	}
+	if order.Status != client.OrderStatusError || order.ErrorStatusCode != strconv.Itoa(http.StatusForbidden) || order.ResultRef() != "" {
+		t.Fatalf("Got order %+v - want a failed order without result", *order)
+	}
+	if secrets := conn.fakeData.List(); len(secrets) != 1 {
+		t.Fatalf("Got %d secrets - want the secrets of the failed order to be removed", len(secrets))
+	}
+
+	order, err = conn.SubmitOrder(ctx, client.KeyOrder{Algorithm: "aes", BitLength: 256})
+	if err != nil {
+		t.Fatalf("Failed to submit order: %v", err)
+	}
+	if _, err = conn.WaitForOrder(ctx, order.OrderRef, time.Millisecond); err != nil {
+		t.Fatalf("Key order failed within the secret quota: %v", err)
+	}
+	if _, err = conn.Create(ctx, "my-key-2", []byte("Hello World")); !errors.Is(err, xerror.QuotaExceeded) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.QuotaExceeded)
+	}
+
+	// Project-specific quotas override the defaults.
+	limit := 3
+	if err = conn.SetProjectQuotas(ctx, DefaultProject, client.ProjectQuotas{Secrets: &limit}); err != nil {
+		t.Fatalf("Failed to set project quotas: %v", err)
+	}
+	if _, err = conn.Create(ctx, "my-key-2", []byte("Hello World")); err != nil {
+		t.Fatalf("Failed to create secret within the project quota: %v", err)
+	}
+	if err = conn.DeleteProjectQuotas(ctx, DefaultProject); err != nil {
+		t.Fatalf("Failed to delete project quotas: %v", err)
+	}
+	if err = conn.DeleteProjectQuotas(ctx, DefaultProject); !errors.Is(err, xerror.NotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.NotFound)
+	}
+}
@@ -0,0 +1,81 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func (c *Client) ListSecretStores(ctx context.Context) ([]client.SecretStore, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	return c.fakeData.ListSecretStores(), nil
+}
+
+func (c *Client) GetSecretStore(ctx context.Context, ref string) (*client.SecretStore, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	store, ok := c.fakeData.GetSecretStore(ref)
+	if !ok {
+		return nil, xerror.ErrSecretStoreNotFound
+	}
+	return &store, nil
+}
+
+func (c *Client) GetGlobalDefaultSecretStore(ctx context.Context) (*client.SecretStore, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	for _, store := range c.fakeData.ListSecretStores() {
+		if store.GlobalDefault {
+			return &store, nil
+		}
+	}
+	return nil, xerror.ErrSecretStoreNotFound
+}
+
+func (c *Client) GetPreferredSecretStore(ctx context.Context) (*client.SecretStore, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	store, ok := c.fakeData.PreferredSecretStore()
+	if !ok {
+		return nil, xerror.ErrSecretStoreNotFound
+	}
+	return &store, nil
+}
+
+func (c *Client) SetPreferredSecretStore(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.SetPreferredSecretStore(ref) {
+		return xerror.ErrSecretStoreNotFound
+	}
+	return nil
+}
+
+func (c *Client) UnsetPreferredSecretStore(ctx context.Context, ref string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if !c.fakeData.UnsetPreferredSecretStore(ref) {
+		return xerror.ErrSecretStoreNotFound
+	}
+	return nil
+}
+
+// SecretStoreOf returns the secret store holding the secret
+// addressed by the given secret reference or UUID. Barbican
+// does not expose this, but it allows tests to verify where
+// secrets end up.
+func (c *Client) SecretStoreOf(ref string) (*client.SecretStore, error) {
+	store, ok := c.fakeData.SecretStoreOf(ref)
+	if !ok {
+		return nil, xerror.ErrKeyNotFound
+	}
+	return &store, nil
+}
@@ -0,0 +1,81 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestSecretStores(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil, WithSecretStores(
+		client.SecretStore{Name: "software", SecretStorePlugin: "store_crypto", CryptoPlugin: "simple_crypto"},
+		client.SecretStore{Name: "hsm", SecretStorePlugin: "store_crypto", CryptoPlugin: "p11_crypto"},
+	))
+
+	stores, err := conn.ListSecretStores(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list secret stores: %v", err)
+	}
+	if len(stores) != 2 || !stores[0].GlobalDefault || stores[1].GlobalDefault {
+		t.Fatalf("Got stores %+v - want the first of two stores as global default", stores)
+	}
+	software, hsm := stores[0], stores[1]
+
+	if _, err = conn.GetPreferredSecretStore(ctx); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+	before, err := conn.Create(ctx, "before", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+
+	if err = conn.SetPreferredSecretStore(ctx, hsm.SecretStoreRef); err != nil {
+		t.Fatalf("Failed to set preferred secret store: %v", err)
+	}
+	preferred, err := conn.GetPreferredSecretStore(ctx)
+	if err != nil || preferred.SecretStoreRef != hsm.SecretStoreRef {
+		t.Fatalf("Got preferred store %v and error '%v' - want '%s'", preferred, err, hsm.Name)
+	}
+	after, err := conn.Create(ctx, "after", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+
+	for i, test := range []struct {
+		Ref   string
+		Store client.SecretStore
+	}{
+		{Ref: before.SecretRef, Store: software}, // 0 - Existing secrets are not moved
+		{Ref: after.SecretRef, Store: hsm},       // 1
+	} {
+		store, err := conn.SecretStoreOf(test.Ref)
+		if err != nil {
+			t.Fatalf("Test %d: failed to find secret store: %v", i, err)
+		}
+		if store.SecretStoreRef != test.Store.SecretStoreRef {
+			t.Fatalf("Test %d: got store '%s' - want '%s'", i, store.Name, test.Store.Name)
+		}
+	}
+
+	if err = conn.UnsetPreferredSecretStore(ctx, software.SecretStoreRef); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Unsetting a store that is not preferred: got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+	if err = conn.UnsetPreferredSecretStore(ctx, hsm.SecretStoreRef); err != nil {
+		t.Fatalf("Failed to unset preferred secret store: %v", err)
+	}
+	if err = conn.SetPreferredSecretStore(ctx, "missing"); !errors.Is(err, xerror.ErrSecretStoreNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrSecretStoreNotFound)
+	}
+
+	global, err := conn.GetGlobalDefaultSecretStore(ctx)
+	if err != nil || global.SecretStoreRef != software.SecretStoreRef {
+		t.Fatalf("Got global default store %v and error '%v' - want '%s'", global, err, software.Name)
+	}
+	if _, err = conn.GetSecretStore(ctx, hsm.SecretStoreRef); err != nil {
+		t.Fatalf("Failed to fetch secret store: %v", err)
+	}
+}
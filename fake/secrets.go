@@ -3,46 +3,182 @@ package fake
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/client"
 	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
 )
 
 // Create stores the given key in Barbican if and only
-// if no entry with the given name existc.
+// if no entry with the given name exists.
 //
-// If no such entry exists, Create returns ErrKeyExistc.
-func (c *Client) Create(ctx context.Context, name string, value []byte) error {
+// If such an entry exists, Create returns ErrKeyExists.
+func (c *Client) Create(ctx context.Context, name string, value []byte) (*client.BarbicanSecret, error) {
+	return c.CreateWithOptions(ctx, name, value,
+		client.WithAlgorithm("aes"),
+		client.WithBitLength(256),
+		client.WithMode("cbc"),
+	)
+}
+
+// CreateWithOptions stores the given value together with
+// exactly the metadata requested by opts.
+//
+// Unlike Barbican, the fake enforces the CreateMode
+// atomically - i.e. CreateIfAbsent never creates a
+// duplicate, even when racing with other creators.
+//
+// The created secret gets a UUID-based secret reference
+// that stays the same for the lifetime of the secret.
+func (c *Client) CreateWithOptions(ctx context.Context, name string, value []byte, opts ...client.SecretOption) (*client.BarbicanSecret, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	const prefix = "couldn't create. Provided object does not match schema 'Secret': "
 	if len(value) == 0 {
-		return fmt.Errorf("couldn't create. Provided object does not match schema 'Secret': If 'payload' specified, must be non empty. Invalid property: 'payload'")
+		return nil, fmt.Errorf(prefix + "If 'payload' specified, must be non empty. Invalid property: 'payload'")
 	}
-	secret := NewSecret(name, value)
-	c.fakeData.Set(name, secret)
-	return nil
+
+	options := client.NewSecretOptions(opts...)
+	switch options.SecretType {
+	case client.SecretTypeSymmetric, client.SecretTypePublic, client.SecretTypePrivate,
+		client.SecretTypePassphrase, client.SecretTypeCertificate, client.SecretTypeOpaque:
+	default:
+		return nil, xerror.NewError(http.StatusBadRequest, fmt.Sprintf(prefix+"'%s' is not one of ['symmetric', 'public', 'private', 'passphrase', 'certificate', 'opaque']", options.SecretType))
+	}
+	if options.BitLength < 0 {
+		return nil, xerror.NewError(http.StatusBadRequest, prefix+"'bit_length' must be greater than zero")
+	}
+	if options.PayloadContentType == "" {
+		return nil, xerror.NewError(http.StatusBadRequest, prefix+"If 'payload' is supplied, 'payload_content_type' must also be supplied")
+	}
+	if !options.Expiration.IsZero() && options.Expiration.Before(time.Now()) {
+		return nil, xerror.NewError(http.StatusBadRequest, prefix+"'expiration' is before current time")
+	}
+
+	if err := c.fakeData.CheckQuota(quotaSecrets); err != nil {
+		return nil, err
+	}
+
+	secret := newSecret(client.NewSecretCreateRequest(name, value, opts...), value)
+	secret.Secret.CreatorID = c.user
+	switch options.CreateMode {
+	case client.CreateIfAbsent:
+		if !c.fakeData.AddIfAbsent(secret) {
+			return nil, xerror.ErrKeyExists
+		}
+	case client.CreateOrReplace:
+		replaced := c.fakeData.FindByName(name)
+		c.fakeData.Add(secret)
+		for _, s := range replaced {
+			if err := c.fakeData.CheckAccess(s.Secret.SecretRef, c.user, true); err != nil {
+				return &secret.Secret, err
+			}
+			c.fakeData.Delete(s.Secret.SecretRef)
+		}
+	default:
+		c.fakeData.Add(secret)
+	}
+	return &secret.Secret, nil
 }
 
 func (c *Client) GetSecret(ctx context.Context, name string) (*client.BarbicanSecret, error) {
-	s, ok := c.fakeData.Get(name)
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	s, err := c.resolveName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.fakeData.CheckAccess(s.Secret.SecretRef, c.user, false); err != nil {
+		return nil, err
+	}
+	return &s.Secret, nil
+}
+
+func (c *Client) GetSecretByID(ctx context.Context, id string) (*client.BarbicanSecret, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	s, ok := c.fakeData.GetByRef(id)
 	if !ok {
 		return nil, xerror.ErrKeyNotFound
 	}
-
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return nil, err
+	}
 	return &s.Secret, nil
 }
 
 func (c *Client) GetSecretWithPayload(ctx context.Context, name string) (*client.BarbicanSecretWithPayload, error) {
-	s, ok := c.fakeData.Get(name)
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	s, err := c.resolveName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.fakeData.CheckAccess(s.Secret.SecretRef, c.user, false); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (c *Client) GetPayloadByID(ctx context.Context, id string) ([]byte, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	s, ok := c.fakeData.GetByRef(id)
 	if !ok {
 		return nil, xerror.ErrKeyNotFound
 	}
-	return &s, nil
+	if err := c.fakeData.CheckAccess(id, c.user, false); err != nil {
+		return nil, err
+	}
+	return s.Payload, nil
 }
 
 func (c *Client) DeleteSecret(ctx context.Context, name string) error {
-	c.fakeData.Delete(name)
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	s, err := c.resolveName(name)
+	if err == xerror.ErrKeyNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.DeleteSecretByID(ctx, s.Secret.SecretRef)
+}
+
+func (c *Client) DeleteSecretByID(ctx context.Context, id string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if err := c.fakeData.CheckAccess(id, c.user, true); err != nil {
+		return err
+	}
+	if !c.fakeData.Delete(id) {
+		return xerror.ErrKeyNotFound
+	}
 	return nil
 }
 
+// resolveName returns the secret with the given name
+// according to the name resolution mode of the client.
+func (c *Client) resolveName(name string) (client.BarbicanSecretWithPayload, error) {
+	secrets := c.fakeData.FindByName(name)
+	if len(secrets) == 0 {
+		return client.BarbicanSecretWithPayload{}, xerror.ErrKeyNotFound
+	}
+	if len(secrets) > 1 && c.nameResolution == client.NameResolutionUnique {
+		return client.BarbicanSecretWithPayload{}, xerror.ErrAmbiguousName
+	}
+	return secrets[0], nil
+}
+
 // List returns a new Iterator over the Barbican.
 //
 // The returned iterator may or may not reflect any
@@ -50,6 +186,9 @@ func (c *Client) DeleteSecret(ctx context.Context, name string) error {
 // creates or deletec. Further, it does not provide any
 // ordering guaranteec.
 func (c *Client) ListSecrets(ctx context.Context) (client.Iterator, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
 	var cancel context.CancelCauseFunc
 	ctx, cancel = context.WithCancelCause(ctx)
 	values := make(chan string, 10)
@@ -71,3 +210,33 @@ func (c *Client) ListSecrets(ctx context.Context) (client.Iterator, error) {
 		cancel: cancel,
 	}, nil
 }
+
+func (c *Client) ListSecretsWithFilter(ctx context.Context, filter client.SecretFilter) (client.SecretIterator, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	secrets, err := c.filterSecrets(c.fakeData.List(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancel context.CancelCauseFunc
+	ctx, cancel = context.WithCancelCause(ctx)
+	values := make(chan client.BarbicanSecret, 10)
+
+	go func() {
+		defer close(values)
+		for _, s := range secrets {
+			select {
+			case values <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &SecretIterator{
+		ch:     values,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestCreateWithOptions(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	for i, test := range createWithOptionsTests {
+		name := fmt.Sprintf("secret-%d", i)
+		secret, err := conn.CreateWithOptions(ctx, name, []byte(test.Value), test.Options...)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but created secret %+v", i, *secret)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to create secret: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		stored, err := conn.GetSecretWithPayload(ctx, name)
+		if err != nil {
+			t.Fatalf("Test %d: failed to fetch secret: %v", i, err)
+		}
+		if string(stored.Payload) != test.Value {
+			t.Fatalf("Test %d: got payload '%s' - want '%s'", i, stored.Payload, test.Value)
+		}
+		options := client.NewSecretOptions(test.Options...)
+		if s := stored.Secret; s.SecretType != string(options.SecretType) || s.ContentTypes["default"] != options.PayloadContentType {
+			t.Fatalf("Test %d: got secret %+v - want options %+v", i, s, options)
+		}
+	}
+}
+
+func TestCreateReturnsSecret(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	secret, err := conn.Create(ctx, "my-key", []byte("Hello World"))
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	if secret.SecretRef == "" || secret.Name != "my-key" || secret.CreatorID != DefaultUser {
+		t.Fatalf("Got unexpected secret %+v", *secret)
+	}
+
+	stored, err := conn.GetSecretByID(ctx, secret.ID())
+	if err != nil {
+		t.Fatalf("Failed to fetch secret by ID: %v", err)
+	}
+	if !reflect.DeepEqual(*stored, *secret) {
+		t.Fatalf("Got %+v - want %+v", *stored, *secret)
+	}
+}
+
+func TestNameResolution(t *testing.T) {
+	ctx := context.Background()
+	for i, test := range nameResolutionTests {
+		conn := newTestConn(t, nil, WithNameResolution(test.Mode))
+		for _, name := range []string{"duplicate", "duplicate", "unique"} {
+			if _, err := conn.CreateWithOptions(ctx, name, []byte("Hello World"), client.WithCreateMode(client.CreateAllowDuplicates)); err != nil {
+				t.Fatalf("Test %d: failed to create secret: %v", i, err)
+			}
+		}
+
+		secret, err := conn.GetSecret(ctx, test.Name)
+		if !errors.Is(err, test.Err) {
+			t.Fatalf("Test %d: got error '%v' - want '%v'", i, err, test.Err)
+		}
+		if err == nil && secret.Name != test.Name {
+			t.Fatalf("Test %d: got secret '%s' - want '%s'", i, secret.Name, test.Name)
+		}
+	}
+}
+
+var nameResolutionTests = []struct {
+	Mode client.NameResolution
+	Name string
+	Err  error
+}{
+	{Mode: client.NameResolutionFirst, Name: "duplicate"},                                // 0
+	{Mode: client.NameResolutionFirst, Name: "missing", Err: xerror.ErrKeyNotFound},      // 1
+	{Mode: client.NameResolutionUnique, Name: "unique"},                                  // 2
+	{Mode: client.NameResolutionUnique, Name: "duplicate", Err: xerror.ErrAmbiguousName}, // 3
+}
+
+var createWithOptionsTests = []struct {
+	Value      string
+	Options    []client.SecretOption
+	ShouldFail bool
+}{
+	{Value: "Hello World"}, // 0
+	{ // 1
+		Value: "0123456789abcdef0123456789abcdef",
+		Options: []client.SecretOption{
+			client.WithSecretType(client.SecretTypeSymmetric),
+			client.WithAlgorithm("aes"),
+			client.WithBitLength(256),
+			client.WithMode("cbc"),
+			client.WithExpiration(time.Now().Add(time.Hour)),
+		},
+	},
+	{ // 2
+		Value:   "correct horse battery staple",
+		Options: []client.SecretOption{client.WithSecretType(client.SecretTypePassphrase), client.WithPayloadContentType(client.ContentTypeTextPlain)},
+	},
+	{Value: "", ShouldFail: true}, // 3
+	{Value: "Hello World", Options: []client.SecretOption{client.WithSecretType("unknown")}, ShouldFail: true},                  // 4
+	{Value: "Hello World", Options: []client.SecretOption{client.WithBitLength(-1)}, ShouldFail: true},                          // 5
+	{Value: "Hello World", Options: []client.SecretOption{client.WithPayloadContentType("")}, ShouldFail: true},                 // 6
+	{Value: "Hello World", Options: []client.SecretOption{client.WithExpiration(time.Now().Add(-time.Hour))}, ShouldFail: true}, // 7
+}
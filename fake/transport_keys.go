@@ -0,0 +1,71 @@
+package fake
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/client"
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// transportKeyPlugin is the name of the secret store
+// plugin providing the fake transport key.
+const transportKeyPlugin = "fake_crypto"
+
+func (c *Client) ListTransportKeys(ctx context.Context, pluginName string) ([]client.TransportKey, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if pluginName != "" && pluginName != transportKeyPlugin {
+		return []client.TransportKey{}, nil
+	}
+	key, err := c.fakeData.TransportKey()
+	if err != nil {
+		return nil, err
+	}
+	return []client.TransportKey{*key}, nil
+}
+
+func (c *Client) GetTransportKey(ctx context.Context, ref string) (*client.TransportKey, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	key, err := c.fakeData.TransportKey()
+	if err != nil {
+		return nil, err
+	}
+	if client.RefID(ref) != key.ID() {
+		return nil, xerror.ErrTransportKeyNotFound
+	}
+	return key, nil
+}
+
+// TransportKey returns the transport key of the fake.
+// It is generated on first use.
+func (f *FakeData) TransportKey() (*client.TransportKey, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.transportKey != nil {
+		return f.transportKey, nil
+	}
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	f.transportKey = &client.TransportKey{
+		Created:         newCreationTime(),
+		PluginName:      transportKeyPlugin,
+		TransportKey:    string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		TransportKeyRef: newRef("transport_keys"),
+		Updated:         newCreationTime(),
+	}
+	return f.transportKey, nil
+}
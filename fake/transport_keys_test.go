@@ -0,0 +1,39 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestTransportKeys(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestConn(t, nil)
+
+	keys, err := conn.ListTransportKeys(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to list transport keys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].PluginName != transportKeyPlugin {
+		t.Fatalf("Got %+v - want the transport key of '%s'", keys, transportKeyPlugin)
+	}
+	if _, err = keys[0].PublicKey(); err != nil {
+		t.Fatalf("Failed to parse transport key: %v", err)
+	}
+	if other, err := conn.ListTransportKeys(ctx, "dogtag_crypto"); err != nil || len(other) != 0 {
+		t.Fatalf("Got %+v and error '%v' - want no transport keys", other, err)
+	}
+
+	key, err := conn.GetTransportKey(ctx, keys[0].TransportKeyRef)
+	if err != nil {
+		t.Fatalf("Failed to fetch transport key: %v", err)
+	}
+	if key.TransportKey != keys[0].TransportKey {
+		t.Fatal("Got a different transport key")
+	}
+	if _, err = conn.GetTransportKey(ctx, "missing"); !errors.Is(err, xerror.ErrTransportKeyNotFound) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrTransportKeyNotFound)
+	}
+}
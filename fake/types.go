@@ -2,15 +2,65 @@ package fake
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/artashesbalabekyan/barbican-sdk-go/client"
 )
 
+// DefaultUser is the ID of the simulated user of
+// a fake connection unless configured otherwise.
+const DefaultUser = "fake-user"
+
+// DefaultProject is the ID of the simulated project
+// all users of a fake connection belong to.
+const DefaultProject = "fake-project"
+
+// DefaultCAName is the name of the certificate
+// authority of a fake without configured CAs.
+const DefaultCAName = "Fake Barbican CA"
+
 type Client struct {
-	fakeData *FakeData
+	fakeData       *FakeData
+	nameResolution client.NameResolution
+	user           string
+	closed         *atomic.Bool // Shared with connections returned by AsUser
+	stores         []client.SecretStore
+	quotas         *client.Quotas
+
+	maxMicroversion client.Microversion
+	version         client.APIVersion
+	cas             []string
 }
 
 type FakeData struct {
-	data map[string]client.BarbicanSecretWithPayload
+	data       map[string]client.BarbicanSecretWithPayload
+	metadata   map[string]map[string]string
+	consumers  map[string][]client.SecretConsumer
+	containers map[string]client.BarbicanContainer
+	acls       map[string]client.ACL
+	orders     map[string]*order
+
+	cas               []*fakeCA // The first CA issues the certificates of orders without CA.
+	projectCAs        []string  // The UUIDs of the project CAs of DefaultProject.
+	preferredCA       string    // The UUID of the project's preferred CA, if any.
+	globalPreferredCA string    // The UUID of the global preferred CA, if any.
+
+	transportKey *client.TransportKey
+
+	stores         []client.SecretStore
+	preferredStore string            // The UUID of the project's preferred secret store, if any.
+	secretStores   map[string]string // Secret UUID => UUID of the secret store holding it.
+
+	quotas        client.Quotas                   // The default quotas.
+	projectQuotas map[string]client.ProjectQuotas // Project ID => project-specific quotas.
 	sync.RWMutex
 }
+
+// order is a fake order together with the number of
+// times it will still be reported as PENDING and the
+// UUIDs of the secrets generated so far.
+type order struct {
+	client.BarbicanOrder
+	pendingPolls int
+	generated    []string
+}
@@ -2,4 +2,7 @@ module github.com/artashesbalabekyan/barbican-sdk-go
 
 go 1.20
 
-require aead.dev/mem v0.2.0
+require (
+	aead.dev/mem v0.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -1,15 +1,83 @@
 package xerror
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 var (
 	ErrKeyExists   = NewError(http.StatusBadRequest, "key already exists")
 	ErrKeyNotFound = NewError(http.StatusNotFound, "key does not exist")
+	ErrForbidden   = NewError(http.StatusForbidden, "access to the resource is forbidden")
+
+	ErrAmbiguousName = NewError(http.StatusConflict, "multiple keys with the given name exist")
+
+	ErrMetadatumExists   = NewError(http.StatusConflict, "metadata key already exists")
+	ErrMetadatumNotFound = NewError(http.StatusNotFound, "metadata key does not exist")
+
+	ErrContainerNotFound = NewError(http.StatusNotFound, "container does not exist")
+	ErrOrderNotFound     = NewError(http.StatusNotFound, "order does not exist")
+	ErrConsumerNotFound  = NewError(http.StatusNotFound, "consumer does not exist")
+
+	ErrSecretStoreNotFound   = NewError(http.StatusNotFound, "secret store does not exist")
+	ErrProjectQuotasNotFound = NewError(http.StatusNotFound, "project quotas do not exist")
+	ErrTransportKeyNotFound  = NewError(http.StatusNotFound, "transport key does not exist")
+	ErrCANotFound            = NewError(http.StatusNotFound, "certificate authority does not exist")
+
+	ErrNotSupported = NewError(http.StatusNotImplemented, "operation is not supported by the negotiated API version")
+)
+
+// quotaReachedPrefix is the beginning of the fault description
+// of Barbican's QuotaReached error.
+const quotaReachedPrefix = "Quota reached for project"
+
+// ErrClosed is returned by a connection that has been closed.
+var ErrClosed = errors.New("connection is closed")
+
+// Kind is a category of errors. Any Error matches its
+// kind via errors.Is - e.g.:
+//
+//	if errors.Is(err, xerror.NotFound) { ... }
+type Kind int
+
+const (
+	NotFound      Kind = iota + 1 // 404 Not Found
+	Conflict                      // 409 Conflict
+	Unauthorized                  // 401 Unauthorized
+	Forbidden                     // 403 Forbidden, except for exceeded quotas
+	QuotaExceeded                 // 403 Forbidden due to an exceeded quota, or 413
+	RateLimited                   // 429 Too Many Requests
 )
 
+func (k Kind) Error() string {
+	switch k {
+	case NotFound:
+		return "not found"
+	case Conflict:
+		return "conflict"
+	case Unauthorized:
+		return "unauthorized"
+	case Forbidden:
+		return "forbidden"
+	case QuotaExceeded:
+		return "quota exceeded"
+	case RateLimited:
+		return "rate limited"
+	default:
+		return fmt.Sprintf("kind %d", int(k))
+	}
+}
+
 type Error struct {
 	code    int
 	message string
+
+	title     string
+	method    string
+	url       string
+	requestID string
 }
 
 func NewError(code int, msg string) Error {
@@ -19,7 +87,103 @@ func NewError(code int, msg string) Error {
 	}
 }
 
+// NewFault returns the error described by a fault response
+// of an OpenStack API, consisting of the HTTP status code, a
+// short title - e.g. "Not Found" - and a detailed message.
+func NewFault(code int, title, msg string) Error {
+	return Error{
+		code:    code,
+		title:   title,
+		message: msg,
+	}
+}
+
+// WithRequest returns a copy of e with the method and URL
+// of the failed request and the request ID assigned by the
+// server, if any.
+func (e Error) WithRequest(method, url, requestID string) Error {
+	e.method, e.url, e.requestID = method, url, requestID
+	return e
+}
+
 // Status returns the HTTP status code of the error.
 func (e Error) Status() int { return e.code }
 
-func (e Error) Error() string { return e.message }
+// Title returns the short title of a fault response, if any.
+func (e Error) Title() string { return e.title }
+
+// Message returns the error message without request context.
+func (e Error) Message() string { return e.message }
+
+// Method returns the method of the failed request, if known.
+func (e Error) Method() string { return e.method }
+
+// URL returns the URL of the failed request, if known.
+func (e Error) URL() string { return e.url }
+
+// RequestID returns the ID the server assigned to the failed
+// request, if any. It helps operators to find the request in
+// the server logs.
+func (e Error) RequestID() string { return e.requestID }
+
+// Kind returns the category of the error, or
+// 0 if the error does not belong to any Kind.
+func (e Error) Kind() Kind {
+	switch e.code {
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		// Barbican reports exceeded quotas as 403 Forbidden with
+		// the description "Quota reached for project <id>. Only
+		// <n> <resources> are allowed."
+		if strings.HasPrefix(e.message, quotaReachedPrefix) {
+			return QuotaExceeded
+		}
+		return Forbidden
+	case http.StatusRequestEntityTooLarge:
+		return QuotaExceeded
+	case http.StatusTooManyRequests:
+		return RateLimited
+	default:
+		return 0
+	}
+}
+
+// Is reports whether e belongs to the given Kind or
+// matches the given Error - e.g. ErrKeyNotFound - except
+// for the request context.
+func (e Error) Is(target error) bool {
+	switch t := target.(type) {
+	case Kind:
+		return t != 0 && e.Kind() == t
+	case Error:
+		return e.code == t.code && e.message == t.message && e.title == t.title
+	default:
+		return false
+	}
+}
+
+func (e Error) Error() string {
+	if e.method == "" && e.url == "" {
+		return e.message
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s: %d", e.method, e.url, e.code)
+	if e.title != "" {
+		sb.WriteString(" " + e.title)
+	} else if text := http.StatusText(e.code); text != "" {
+		sb.WriteString(" " + text)
+	}
+	if e.message != "" && e.message != e.title {
+		sb.WriteString(": " + e.message)
+	}
+	if e.requestID != "" {
+		sb.WriteString(" (request-id: " + e.requestID + ")")
+	}
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorKind(t *testing.T) {
+	for i, test := range errorKindTests {
+		if kind := test.Err.Kind(); kind != test.Kind {
+			t.Fatalf("Test %d: got kind '%v' - want '%v'", i, kind, test.Kind)
+		}
+		for _, kind := range []Kind{NotFound, Conflict, Unauthorized, Forbidden, QuotaExceeded, RateLimited} {
+			if is := errors.Is(test.Err, kind); is != (kind == test.Kind) {
+				t.Fatalf("Test %d: errors.Is(err, %v) = %v - want %v", i, kind, is, kind == test.Kind)
+			}
+		}
+		if wrapped := fmt.Errorf("barbican: %w", test.Err); test.Kind != 0 && !errors.Is(wrapped, test.Kind) {
+			t.Fatalf("Test %d: wrapped error does not match kind '%v'", i, test.Kind)
+		}
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	err := ErrKeyNotFound.WithRequest(http.MethodGet, "http://localhost:9311/v1/secrets/1", "req-1")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Error with request context does not match '%v'", ErrKeyNotFound)
+	}
+	if errors.Is(err, ErrContainerNotFound) {
+		t.Fatalf("Error matches '%v'", ErrContainerNotFound)
+	}
+	if errors.Is(NewFault(http.StatusNotFound, "Not Found", ErrKeyNotFound.Message()), ErrKeyNotFound) {
+		t.Fatal("Fault with title matches an error without title")
+	}
+	if errors.Is(err, Kind(0)) {
+		t.Fatal("Error matches the zero Kind")
+	}
+
+	if ErrKeyExists.Status() != http.StatusBadRequest || errors.Is(ErrKeyExists, Conflict) {
+		t.Fatalf("Got %d - want ErrKeyExists to stay a %d without kind", ErrKeyExists.Status(), http.StatusBadRequest)
+	}
+	var e Error
+	if errors.As(ErrClosed, &e) || errors.Is(ErrClosed, NotFound) {
+		t.Fatalf("ErrClosed is an %T", e)
+	}
+	if !errors.Is(fmt.Errorf("barbican: %w", ErrClosed), ErrClosed) {
+		t.Fatal("Wrapped ErrClosed does not match ErrClosed")
+	}
+}
+
+func TestErrorError(t *testing.T) {
+	for i, test := range errorErrorTests {
+		if s := test.Err.Error(); s != test.String {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, s, test.String)
+		}
+	}
+}
+
+var errorKindTests = []struct {
+	Err  Error
+	Kind Kind
+}{
+	{Err: ErrKeyNotFound, Kind: NotFound},                                                                              // 0
+	{Err: NewFault(http.StatusNotFound, "Not Found", "Secret not found."), Kind: NotFound},                             // 1
+	{Err: ErrMetadatumExists, Kind: Conflict},                                                                          // 2
+	{Err: NewError(http.StatusUnauthorized, "The request you have made requires authentication."), Kind: Unauthorized}, // 3
+	{Err: ErrForbidden, Kind: Forbidden},                                                                               // 4
+	{ // 5
+		Err:  NewFault(http.StatusForbidden, "Forbidden", "Quota reached for project 6f0b1f2e. Only 10 secrets are allowed."),
+		Kind: QuotaExceeded,
+	},
+	{ // 6 - Only Barbican's quota fault is an exceeded quota
+		Err:  NewFault(http.StatusForbidden, "Forbidden", "You are not authorized to update the quota of another project."),
+		Kind: Forbidden,
+	},
+	{Err: NewError(http.StatusRequestEntityTooLarge, "Request Entity Too Large"), Kind: QuotaExceeded}, // 7
+	{Err: NewError(http.StatusTooManyRequests, "Too Many Requests"), Kind: RateLimited},                // 8
+	{Err: ErrKeyExists}, // 9
+	{Err: NewError(http.StatusInternalServerError, "Internal Server Error")}, // 10
+}
+
+var errorErrorTests = []struct {
+	Err    Error
+	String string
+}{
+	{Err: ErrKeyNotFound, String: "key does not exist"}, // 0
+	{ // 1
+		Err:    NewFault(http.StatusNotFound, "Not Found", "Secret not found.").WithRequest(http.MethodGet, "http://localhost:9311/v1/secrets/1", "req-1"),
+		String: "GET http://localhost:9311/v1/secrets/1: 404 Not Found: Secret not found. (request-id: req-1)",
+	},
+	{ // 2
+		Err:    NewError(http.StatusServiceUnavailable, "").WithRequest(http.MethodPost, "http://localhost:9311/v1/secrets", ""),
+		String: "POST http://localhost:9311/v1/secrets: 503 Service Unavailable",
+	},
+}
@@ -0,0 +1,187 @@
+package xhttp
+
+import "errors"
+
+// Authenticator provides the identity part of a Keystone
+// token request - i.e. the authentication method(s) and
+// the corresponding credentials.
+//
+// It is implemented by PasswordAuth, TokenAuth,
+// ApplicationCredentialAuth, TOTPAuth and MultiFactorAuth.
+type Authenticator interface {
+	Identity() (AuthIdentity, error)
+}
+
+// PasswordAuth authenticates a user via password. The user
+// is identified either by UserID or by Username and the user
+// domain, given by UserDomainID or UserDomainName.
+type PasswordAuth struct {
+	UserID         string
+	Username       string
+	UserDomainID   string
+	UserDomainName string
+	Password       string
+}
+
+func (a PasswordAuth) Identity() (AuthIdentity, error) {
+	user, err := newUser(a.UserID, a.Username, a.UserDomainID, a.UserDomainName)
+	if err != nil {
+		return AuthIdentity{}, err
+	}
+	if a.Password == "" {
+		return AuthIdentity{}, errors.New("password is empty")
+	}
+	user.Password = a.Password
+	return AuthIdentity{
+		Methods:  []string{"password"},
+		Password: &Password{User: user},
+	}, nil
+}
+
+// TokenAuth authenticates via a pre-issued Keystone token.
+// The token is exchanged for a new token with the configured
+// scope.
+type TokenAuth struct {
+	Token string
+}
+
+func (a TokenAuth) Identity() (AuthIdentity, error) {
+	if a.Token == "" {
+		return AuthIdentity{}, errors.New("token is empty")
+	}
+	return AuthIdentity{
+		Methods: []string{"token"},
+		Token:   &TokenIdentity{ID: a.Token},
+	}, nil
+}
+
+// ApplicationCredentialAuth authenticates via an application
+// credential. The credential is identified either by ID or by
+// Name together with its owner - given by UserID or by Username
+// and the user domain.
+//
+// Application credentials are bound to a project, so the
+// resulting token is always scoped to that project.
+type ApplicationCredentialAuth struct {
+	ID             string
+	Name           string
+	Secret         string
+	UserID         string
+	Username       string
+	UserDomainID   string
+	UserDomainName string
+}
+
+func (a ApplicationCredentialAuth) Identity() (AuthIdentity, error) {
+	if a.Secret == "" {
+		return AuthIdentity{}, errors.New("application credential secret is empty")
+	}
+	credential := &ApplicationCredential{
+		ID:     a.ID,
+		Secret: a.Secret,
+	}
+	if a.ID == "" {
+		if a.Name == "" {
+			return AuthIdentity{}, errors.New("application credential id and name are empty")
+		}
+		user, err := newUser(a.UserID, a.Username, a.UserDomainID, a.UserDomainName)
+		if err != nil {
+			return AuthIdentity{}, err
+		}
+		credential.Name = a.Name
+		credential.User = &user
+	}
+	return AuthIdentity{
+		Methods:               []string{"application_credential"},
+		ApplicationCredential: credential,
+	}, nil
+}
+
+// TOTPAuth authenticates a user via a time-based one-time
+// passcode. The user is identified either by UserID or by
+// Username and the user domain.
+//
+// Passcodes expire quickly, so TOTPAuth is usually combined
+// with another method via MultiFactorAuth. Once the passcode
+// has expired, the token cannot be renewed anymore.
+type TOTPAuth struct {
+	UserID         string
+	Username       string
+	UserDomainID   string
+	UserDomainName string
+	Passcode       string
+}
+
+func (a TOTPAuth) Identity() (AuthIdentity, error) {
+	user, err := newUser(a.UserID, a.Username, a.UserDomainID, a.UserDomainName)
+	if err != nil {
+		return AuthIdentity{}, err
+	}
+	if a.Passcode == "" {
+		return AuthIdentity{}, errors.New("passcode is empty")
+	}
+	user.Passcode = a.Passcode
+	return AuthIdentity{
+		Methods: []string{"totp"},
+		TOTP:    &TOTP{User: user},
+	}, nil
+}
+
+// MultiFactorAuth authenticates via multiple methods at once,
+// e.g. a password and a TOTP passcode, as required by Keystone
+// users with multi-factor authentication rules.
+type MultiFactorAuth []Authenticator
+
+func (a MultiFactorAuth) Identity() (AuthIdentity, error) {
+	if len(a) == 0 {
+		return AuthIdentity{}, errors.New("no authentication methods")
+	}
+
+	var identity AuthIdentity
+	for _, authenticator := range a {
+		factor, err := authenticator.Identity()
+		if err != nil {
+			return AuthIdentity{}, err
+		}
+		for _, method := range factor.Methods {
+			for _, m := range identity.Methods {
+				if m == method {
+					return AuthIdentity{}, errors.New("duplicate authentication method: " + method)
+				}
+			}
+		}
+		identity.Methods = append(identity.Methods, factor.Methods...)
+		if factor.Password != nil {
+			identity.Password = factor.Password
+		}
+		if factor.Token != nil {
+			identity.Token = factor.Token
+		}
+		if factor.ApplicationCredential != nil {
+			identity.ApplicationCredential = factor.ApplicationCredential
+		}
+		if factor.TOTP != nil {
+			identity.TOTP = factor.TOTP
+		}
+	}
+	return identity, nil
+}
+
+// newUser returns the user identified either by id or
+// by name and domain ID or name.
+func newUser(id, name, domainID, domainName string) (User, error) {
+	if id != "" {
+		return User{ID: id}, nil
+	}
+	if name == "" {
+		return User{}, errors.New("username is empty")
+	}
+	switch {
+	case domainID != "":
+		return User{Name: name, Domain: &Domain{ID: domainID}}, nil
+	case domainName != "":
+		return User{Name: name, Domain: &Domain{Name: domainName}}, nil
+	default:
+		return User{}, errors.New("user domain name is empty")
+	}
+}
@@ -0,0 +1,187 @@
+package xhttp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestAuthenticatorIdentity(t *testing.T) {
+	for i, test := range authenticatorIdentityTests {
+		identity, err := test.Auth.Identity()
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but returned %+v", i, identity)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to create identity: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		b, err := json.Marshal(identity)
+		if err != nil {
+			t.Fatalf("Test %d: failed to marshal identity: %v", i, err)
+		}
+		if string(b) != test.JSON {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, b, test.JSON)
+		}
+	}
+}
+
+func TestCredentialsAuthenticator(t *testing.T) {
+	credentials := Credentials{Username: "admin", UserDomainName: "Default", Password: "secret"}
+	if auth, ok := credentials.Authenticator().(PasswordAuth); !ok || auth.Username != "admin" || auth.Password != "secret" {
+		t.Fatalf("Got %+v - want password authentication of 'admin'", credentials.Authenticator())
+	}
+
+	credentials.Auth = TokenAuth{Token: "gAAAAAB"}
+	if _, ok := credentials.Authenticator().(TokenAuth); !ok {
+		t.Fatalf("Got %T - want %T", credentials.Authenticator(), TokenAuth{})
+	}
+}
+
+var authenticatorIdentityTests = []struct {
+	Auth       Authenticator
+	JSON       string
+	ShouldFail bool
+}{
+	{ // 0
+		Auth: PasswordAuth{Username: "admin", UserDomainName: "Default", Password: "secret"},
+		JSON: `{"methods":["password"],"password":{"user":{"domain":{"name":"Default"},"name":"admin","password":"secret"}}}`,
+	},
+	{ // 1 - User IDs need no domain
+		Auth: PasswordAuth{UserID: "user-id", Password: "secret"},
+		JSON: `{"methods":["password"],"password":{"user":{"id":"user-id","password":"secret"}}}`,
+	},
+	{ // 2
+		Auth: PasswordAuth{Username: "admin", UserDomainID: "default", Password: "secret"},
+		JSON: `{"methods":["password"],"password":{"user":{"domain":{"id":"default"},"name":"admin","password":"secret"}}}`,
+	},
+	{Auth: PasswordAuth{Username: "admin", Password: "secret"}, ShouldFail: true},               // 3 - No user domain
+	{Auth: PasswordAuth{Username: "admin", UserDomainName: "Default"}, ShouldFail: true},        // 4 - No password
+	{Auth: PasswordAuth{UserDomainName: "Default", Password: "secret"}, ShouldFail: true},       // 5 - No user
+	{Auth: TokenAuth{Token: "gAAAAAB"}, JSON: `{"methods":["token"],"token":{"id":"gAAAAAB"}}`}, // 6
+	{Auth: TokenAuth{}, ShouldFail: true},                                                       // 7
+	{ // 8
+		Auth: ApplicationCredentialAuth{ID: "app-id", Secret: "app-secret"},
+		JSON: `{"methods":["application_credential"],"application_credential":{"id":"app-id","secret":"app-secret"}}`,
+	},
+	{ // 9 - Names need the owning user
+		Auth: ApplicationCredentialAuth{Name: "app", Secret: "app-secret", Username: "admin", UserDomainName: "Default"},
+		JSON: `{"methods":["application_credential"],"application_credential":{"name":"app","secret":"app-secret","user":{"domain":{"name":"Default"},"name":"admin"}}}`,
+	},
+	{Auth: ApplicationCredentialAuth{Name: "app", Secret: "app-secret"}, ShouldFail: true}, // 10
+	{Auth: ApplicationCredentialAuth{ID: "app-id"}, ShouldFail: true},                      // 11
+	{Auth: ApplicationCredentialAuth{Secret: "app-secret"}, ShouldFail: true},              // 12
+	{ // 13
+		Auth: TOTPAuth{UserID: "user-id", Passcode: "123456"},
+		JSON: `{"methods":["totp"],"totp":{"user":{"id":"user-id","passcode":"123456"}}}`,
+	},
+	{Auth: TOTPAuth{UserID: "user-id"}, ShouldFail: true}, // 14
+	{ // 15
+		Auth: MultiFactorAuth{
+			PasswordAuth{UserID: "user-id", Password: "secret"},
+			TOTPAuth{UserID: "user-id", Passcode: "123456"},
+		},
+		JSON: `{"methods":["password","totp"],"password":{"user":{"id":"user-id","password":"secret"}},"totp":{"user":{"id":"user-id","passcode":"123456"}}}`,
+	},
+	{Auth: MultiFactorAuth{}, ShouldFail: true}, // 16
+	{ // 17 - Duplicate methods
+		Auth:       MultiFactorAuth{TokenAuth{Token: "a"}, TokenAuth{Token: "b"}},
+		ShouldFail: true,
+	},
+	{ // 18 - Invalid factors
+		Auth:       MultiFactorAuth{PasswordAuth{UserID: "user-id", Password: "secret"}, TOTPAuth{UserID: "user-id"}},
+		ShouldFail: true,
+	},
+}
+
+func TestAuthenticateScope(t *testing.T) {
+	server := newTestServer(t)
+	for i, test := range authenticateScopeTests {
+		config := server.config()
+		config.Login = test.Login
+		config.Login.AuthUrl = server.URL + "/v3"
+		if config.Login.Auth == nil {
+			config.Login.Auth = PasswordAuth{UserID: "user-id", Password: "secret"}
+		}
+
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("Test %d: failed to create client: %v", i, err)
+		}
+		err = client.Authenticate(context.Background(), config)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but authenticated", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to authenticate: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		server.lock.Lock()
+		body := server.authBody
+		server.lock.Unlock()
+
+		var request struct {
+			Auth map[string]json.RawMessage `json:"auth"`
+		}
+		if err = json.Unmarshal(body, &request); err != nil {
+			t.Fatalf("Test %d: failed to parse auth request '%s': %v", i, body, err)
+		}
+		if scope := string(request.Auth["scope"]); scope != test.Scope {
+			t.Fatalf("Test %d: got scope '%s' - want '%s'", i, scope, test.Scope)
+		}
+	}
+}
+
+func TestScopeUnmarshalJSON(t *testing.T) {
+	for i, s := range []string{`"unscoped"`, `{"project":{"id":"project-id"}}`, `{"system":{"all":true}}`} {
+		var scope Scope
+		if err := json.Unmarshal([]byte(s), &scope); err != nil {
+			t.Fatalf("Test %d: failed to parse scope: %v", i, err)
+		}
+		b, err := json.Marshal(scope)
+		if err != nil {
+			t.Fatalf("Test %d: failed to marshal scope: %v", i, err)
+		}
+		if string(b) != s {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, b, s)
+		}
+	}
+}
+
+var authenticateScopeTests = []struct {
+	Login      Credentials
+	Scope      string // The JSON encoded scope, empty if omitted
+	ShouldFail bool
+}{
+	{ // 0
+		Login: Credentials{ProjectName: "admin", ProjectDomain: "Default"},
+		Scope: `{"project":{"domain":{"name":"Default"},"name":"admin"}}`,
+	},
+	{ // 1
+		Login: Credentials{ProjectName: "admin", ProjectDomainID: "default"},
+		Scope: `{"project":{"domain":{"id":"default"},"name":"admin"}}`,
+	},
+	{ // 2 - Project IDs take precedence over names
+		Login: Credentials{ProjectID: "project-id", ProjectName: "admin"},
+		Scope: `{"project":{"id":"project-id"}}`,
+	},
+	{Login: Credentials{DomainID: "default"}, Scope: `{"domain":{"id":"default"}}`},      // 3
+	{Login: Credentials{DomainName: "Default"}, Scope: `{"domain":{"name":"Default"}}`},  // 4
+	{Login: Credentials{System: true}, Scope: `{"system":{"all":true}}`},                 // 5
+	{Login: Credentials{Unscoped: true}, Scope: `"unscoped"`},                            // 6
+	{Login: Credentials{}, ShouldFail: true},                                             // 7 - No scope
+	{Login: Credentials{ProjectName: "admin"}, ShouldFail: true},                         // 8 - No project domain
+	{Login: Credentials{ProjectID: "project-id", DomainID: "default"}, ShouldFail: true}, // 9
+	{Login: Credentials{ProjectID: "project-id", System: true}, ShouldFail: true},        // 10
+	{Login: Credentials{DomainName: "Default", Unscoped: true}, ShouldFail: true},        // 11
+	{Login: Credentials{System: true, Unscoped: true}, ShouldFail: true},                 // 12
+	{ // 13 - Application credentials are not scoped explicitly
+		Login: Credentials{ProjectID: "project-id", Auth: ApplicationCredentialAuth{ID: "app-id", Secret: "app-secret"}},
+	},
+}
@@ -0,0 +1,79 @@
+package xhttp
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultServiceType is the catalog service type of Barbican.
+	DefaultServiceType = "key-manager"
+
+	// DefaultInterface is the catalog endpoint interface used
+	// unless configured otherwise.
+	DefaultInterface = "public"
+)
+
+// Catalog is the Keystone service catalog returned
+// together with a scoped token.
+type Catalog []CatalogService
+
+type CatalogService struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Endpoints []CatalogEndpoint `json:"endpoints"`
+}
+
+type CatalogEndpoint struct {
+	ID        string `json:"id"`
+	Interface string `json:"interface"`
+	Region    string `json:"region"`
+	RegionID  string `json:"region_id"`
+	URL       string `json:"url"`
+}
+
+// Endpoint returns the URL of the endpoint of the service with
+// the given type - and, if not empty, name - in the given region
+// with the given interface (public, internal or admin).
+//
+// If region is empty, the service must have exactly one matching
+// endpoint URL across all regions.
+func (c Catalog) Endpoint(serviceType, serviceName, region, iface string) (string, error) {
+	iface = strings.TrimSuffix(strings.ToLower(iface), "url") // Accept legacy names like publicURL.
+	var urls []string
+	for _, service := range c {
+		if service.Type != serviceType || (serviceName != "" && service.Name != serviceName) {
+			continue
+		}
+		for _, endpoint := range service.Endpoints {
+			if endpoint.Interface != iface {
+				continue
+			}
+			if region != "" && endpoint.Region != region && endpoint.RegionID != region {
+				continue
+			}
+			if !contains(urls, endpoint.URL) {
+				urls = append(urls, endpoint.URL)
+			}
+		}
+	}
+
+	switch len(urls) {
+	case 0:
+		return "", fmt.Errorf("service catalog does not contain a %s endpoint of service type '%s' in region '%s'", iface, serviceType, region)
+	case 1:
+		return urls[0], nil
+	default:
+		return "", fmt.Errorf("service catalog contains %d %s endpoints of service type '%s': specify a region", len(urls), iface, serviceType)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
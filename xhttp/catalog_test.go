@@ -0,0 +1,134 @@
+package xhttp
+
+import "testing"
+
+func TestCatalogEndpoint(t *testing.T) {
+	for i, test := range catalogEndpointTests {
+		url, err := testCatalog.Endpoint(test.ServiceType, test.ServiceName, test.Region, test.Interface)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but returned '%s'", i, url)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to select endpoint: %v", i, err)
+		}
+		if url != test.URL {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, url, test.URL)
+		}
+	}
+}
+
+func TestDiscoverEndpoint(t *testing.T) {
+	client := &Client{}
+	if _, err := client.DiscoverEndpoint(Config{Region: "RegionOne"}); err == nil {
+		t.Fatal("Discovered an endpoint without a service catalog")
+	}
+
+	client.catalog = testCatalog
+	url, err := client.DiscoverEndpoint(Config{Region: "RegionOne"})
+	if err != nil {
+		t.Fatalf("Failed to discover endpoint: %v", err)
+	}
+	if url != "https://barbican.one:9311" {
+		t.Fatalf("Got '%s' - want the default public key-manager endpoint 'https://barbican.one:9311'", url)
+	}
+}
+
+var testCatalog = Catalog{
+	{
+		Name: "keystone",
+		Type: "identity",
+		Endpoints: []CatalogEndpoint{
+			{Interface: "public", Region: "RegionOne", URL: "https://keystone.one:5000"},
+		},
+	},
+	{
+		Name: "barbican",
+		Type: "key-manager",
+		Endpoints: []CatalogEndpoint{
+			{Interface: "public", Region: "RegionOne", RegionID: "RegionOne", URL: "https://barbican.one:9311"},
+			{Interface: "internal", Region: "RegionOne", RegionID: "RegionOne", URL: "http://barbican.one.internal:9311"},
+			{Interface: "admin", Region: "RegionOne", RegionID: "RegionOne", URL: "http://barbican.one.internal:9311"},
+			{Interface: "public", Region: "RegionTwo", RegionID: "region-2", URL: "https://barbican.two:9311"},
+		},
+	},
+	{
+		Name: "barbican-hsm",
+		Type: "key-manager",
+		Endpoints: []CatalogEndpoint{
+			{Interface: "internal", Region: "RegionThree", URL: "http://barbican-hsm.three:9311"},
+		},
+	},
+}
+
+var catalogEndpointTests = []struct {
+	ServiceType string
+	ServiceName string
+	Region      string
+	Interface   string
+	URL         string
+	ShouldFail  bool
+}{
+	{ // 0
+		ServiceType: "key-manager",
+		Region:      "RegionOne",
+		Interface:   "public",
+		URL:         "https://barbican.one:9311",
+	},
+	{ // 1
+		ServiceType: "key-manager",
+		Region:      "RegionOne",
+		Interface:   "internal",
+		URL:         "http://barbican.one.internal:9311",
+	},
+	{ // 2 - Legacy interface names
+		ServiceType: "key-manager",
+		Region:      "RegionOne",
+		Interface:   "publicURL",
+		URL:         "https://barbican.one:9311",
+	},
+	{ // 3 - Region IDs
+		ServiceType: "key-manager",
+		Region:      "region-2",
+		Interface:   "public",
+		URL:         "https://barbican.two:9311",
+	},
+	{ // 4 - Ambiguous without region
+		ServiceType: "key-manager",
+		Interface:   "public",
+		ShouldFail:  true,
+	},
+	{ // 5 - Unique without region
+		ServiceType: "key-manager",
+		Interface:   "admin",
+		URL:         "http://barbican.one.internal:9311",
+	},
+	{ // 6 - Ambiguous across services
+		ServiceType: "key-manager",
+		Interface:   "internal",
+		ShouldFail:  true,
+	},
+	{ // 7 - Service names
+		ServiceType: "key-manager",
+		ServiceName: "barbican-hsm",
+		Interface:   "internal",
+		URL:         "http://barbican-hsm.three:9311",
+	},
+	{ // 8
+		ServiceType: "key-manager",
+		ServiceName: "barbican-hsm",
+		Interface:   "public",
+		ShouldFail:  true,
+	},
+	{ // 9
+		ServiceType: "key-manager",
+		Region:      "RegionFour",
+		Interface:   "public",
+		ShouldFail:  true,
+	},
+	{ // 10
+		ServiceType: "object-store",
+		Region:      "RegionOne",
+		Interface:   "public",
+		ShouldFail:  true,
+	},
+}
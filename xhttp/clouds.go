@@ -0,0 +1,326 @@
+package xhttp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cloud is a cloud entry of a clouds.yaml file. The same
+// structure is filled from the OS_* environment variables.
+type cloud struct {
+	Auth                       cloudAuth `yaml:"auth"`
+	AuthType                   string    `yaml:"auth_type"`
+	AuthMethods                []string  `yaml:"auth_methods"`
+	RegionName                 string    `yaml:"region_name"`
+	Interface                  string    `yaml:"interface"`
+	KeyManagerEndpointOverride string    `yaml:"key_manager_endpoint_override"`
+	KeyManagerServiceType      string    `yaml:"key_manager_service_type"`
+	KeyManagerServiceName      string    `yaml:"key_manager_service_name"`
+	CACert                     string    `yaml:"cacert"`
+	Cert                       string    `yaml:"cert"`
+	Key                        string    `yaml:"key"`
+	Verify                     *bool     `yaml:"verify"`
+}
+
+type cloudAuth struct {
+	AuthURL                     string `yaml:"auth_url"`
+	Username                    string `yaml:"username"`
+	UserID                      string `yaml:"user_id"`
+	Password                    string `yaml:"password"`
+	UserDomainName              string `yaml:"user_domain_name"`
+	UserDomainID                string `yaml:"user_domain_id"`
+	ProjectName                 string `yaml:"project_name"`
+	ProjectID                   string `yaml:"project_id"`
+	ProjectDomainName           string `yaml:"project_domain_name"`
+	ProjectDomainID             string `yaml:"project_domain_id"`
+	DomainName                  string `yaml:"domain_name"`
+	DomainID                    string `yaml:"domain_id"`
+	DefaultDomain               string `yaml:"default_domain"`
+	SystemScope                 string `yaml:"system_scope"`
+	Token                       string `yaml:"token"`
+	ApplicationCredentialID     string `yaml:"application_credential_id"`
+	ApplicationCredentialName   string `yaml:"application_credential_name"`
+	ApplicationCredentialSecret string `yaml:"application_credential_secret"`
+	Passcode                    string `yaml:"passcode"`
+}
+
+// LoadCloudConfig returns the Config of the cloud with the given
+// name from clouds.yaml, merged with the matching entry of
+// secure.yaml, if any. If name is empty, the OS_CLOUD environment
+// variable is used.
+//
+// The files are searched like the OpenStack CLI does: the file
+// named by OS_CLIENT_CONFIG_FILE (resp. OS_CLIENT_SECURE_FILE),
+// then the current directory, ~/.config/openstack and
+// /etc/openstack.
+func LoadCloudConfig(name string) (*Config, error) {
+	if name == "" {
+		name = os.Getenv("OS_CLOUD")
+	}
+	if name == "" {
+		return nil, errors.New("clouds.yaml: no cloud name given and OS_CLOUD is not set")
+	}
+
+	cloudsFile, ok := findConfigFile("OS_CLIENT_CONFIG_FILE", "clouds.yaml", "clouds.yml")
+	if !ok {
+		return nil, errors.New("clouds.yaml: file not found")
+	}
+	clouds, err := readClouds(cloudsFile)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := clouds[name]
+	if !ok {
+		return nil, fmt.Errorf("clouds.yaml: cloud '%s' not found in %s", name, cloudsFile)
+	}
+
+	if secureFile, ok := findConfigFile("OS_CLIENT_SECURE_FILE", "secure.yaml", "secure.yml"); ok {
+		secure, err := readClouds(secureFile)
+		if err != nil {
+			return nil, err
+		}
+		if secureEntry, ok := secure[name]; ok {
+			entry = mergeYAML(entry, secureEntry)
+		}
+	}
+
+	// Round-trip the merged entry to decode it into a cloud.
+	raw, err := yaml.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var c cloud
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("clouds.yaml: invalid cloud '%s': %v", name, err)
+	}
+	return c.config()
+}
+
+// ConfigFromEnv returns the Config described by the
+// OS_* environment variables, e.g. OS_AUTH_URL,
+// OS_USERNAME and OS_PASSWORD.
+func ConfigFromEnv() (*Config, error) {
+	env := func(keys ...string) string {
+		for _, key := range keys {
+			if v := os.Getenv(key); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	c := cloud{
+		AuthType:                   env("OS_AUTH_TYPE"),
+		RegionName:                 env("OS_REGION_NAME"),
+		Interface:                  env("OS_INTERFACE", "OS_ENDPOINT_TYPE"),
+		KeyManagerEndpointOverride: env("OS_KEY_MANAGER_ENDPOINT_OVERRIDE"),
+		KeyManagerServiceType:      env("OS_KEY_MANAGER_SERVICE_TYPE"),
+		KeyManagerServiceName:      env("OS_KEY_MANAGER_SERVICE_NAME"),
+		CACert:                     env("OS_CACERT"),
+		Cert:                       env("OS_CERT"),
+		Key:                        env("OS_KEY"),
+		Auth: cloudAuth{
+			AuthURL:                     env("OS_AUTH_URL"),
+			Username:                    env("OS_USERNAME"),
+			UserID:                      env("OS_USER_ID"),
+			Password:                    env("OS_PASSWORD"),
+			UserDomainName:              env("OS_USER_DOMAIN_NAME"),
+			UserDomainID:                env("OS_USER_DOMAIN_ID"),
+			ProjectName:                 env("OS_PROJECT_NAME", "OS_TENANT_NAME"),
+			ProjectID:                   env("OS_PROJECT_ID", "OS_TENANT_ID"),
+			ProjectDomainName:           env("OS_PROJECT_DOMAIN_NAME"),
+			ProjectDomainID:             env("OS_PROJECT_DOMAIN_ID"),
+			DomainName:                  env("OS_DOMAIN_NAME"),
+			DomainID:                    env("OS_DOMAIN_ID"),
+			DefaultDomain:               env("OS_DEFAULT_DOMAIN"),
+			SystemScope:                 env("OS_SYSTEM_SCOPE"),
+			Token:                       env("OS_TOKEN"),
+			ApplicationCredentialID:     env("OS_APPLICATION_CREDENTIAL_ID"),
+			ApplicationCredentialName:   env("OS_APPLICATION_CREDENTIAL_NAME"),
+			ApplicationCredentialSecret: env("OS_APPLICATION_CREDENTIAL_SECRET"),
+			Passcode:                    env("OS_PASSCODE"),
+		},
+	}
+	if insecure := env("OS_INSECURE"); insecure != "" {
+		skip, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, fmt.Errorf("environment: invalid OS_INSECURE '%s'", insecure)
+		}
+		verify := !skip
+		c.Verify = &verify
+	}
+	if methods := env("OS_AUTH_METHODS"); methods != "" {
+		c.AuthMethods = strings.Split(methods, ",")
+	}
+	if c.Auth.AuthURL == "" {
+		return nil, errors.New("environment: OS_AUTH_URL is not set")
+	}
+	return c.config()
+}
+
+// config converts the cloud into a Config.
+func (c *cloud) config() (*Config, error) {
+	a := c.Auth
+	if a.DefaultDomain != "" {
+		if a.UserDomainName == "" && a.UserDomainID == "" {
+			a.UserDomainID = a.DefaultDomain
+		}
+		if a.ProjectName != "" && a.ProjectDomainName == "" && a.ProjectDomainID == "" {
+			a.ProjectDomainID = a.DefaultDomain
+		}
+	}
+
+	authenticator, err := c.authenticator(c.AuthType, a)
+	if err != nil {
+		return nil, err
+	}
+	if a.SystemScope != "" && a.SystemScope != "all" {
+		return nil, fmt.Errorf("unsupported system_scope '%s'", a.SystemScope)
+	}
+	return &Config{
+		Endpoint:    c.KeyManagerEndpointOverride,
+		ServiceType: c.KeyManagerServiceType,
+		ServiceName: c.KeyManagerServiceName,
+		Region:      c.RegionName,
+		Interface:   c.Interface,
+		TLS: TLSConfig{
+			CAFile:             c.CACert,
+			CertFile:           c.Cert,
+			KeyFile:            c.Key,
+			InsecureSkipVerify: c.Verify != nil && !*c.Verify,
+		},
+		Login: Credentials{
+			AuthUrl:         a.AuthURL,
+			ProjectName:     a.ProjectName,
+			ProjectDomain:   a.ProjectDomainName,
+			ProjectID:       a.ProjectID,
+			ProjectDomainID: a.ProjectDomainID,
+			DomainName:      a.DomainName,
+			DomainID:        a.DomainID,
+			System:          a.SystemScope == "all",
+			Auth:            authenticator,
+		},
+	}, nil
+}
+
+// authenticator returns the Authenticator for the given
+// clouds.yaml auth_type.
+func (c *cloud) authenticator(authType string, a cloudAuth) (Authenticator, error) {
+	switch strings.TrimPrefix(strings.ToLower(authType), "v3") {
+	case "", "password":
+		return PasswordAuth{
+			UserID:         a.UserID,
+			Username:       a.Username,
+			UserDomainID:   a.UserDomainID,
+			UserDomainName: a.UserDomainName,
+			Password:       a.Password,
+		}, nil
+	case "token":
+		return TokenAuth{Token: a.Token}, nil
+	case "applicationcredential":
+		return ApplicationCredentialAuth{
+			ID:             a.ApplicationCredentialID,
+			Name:           a.ApplicationCredentialName,
+			Secret:         a.ApplicationCredentialSecret,
+			UserID:         a.UserID,
+			Username:       a.Username,
+			UserDomainID:   a.UserDomainID,
+			UserDomainName: a.UserDomainName,
+		}, nil
+	case "totp":
+		return TOTPAuth{
+			UserID:         a.UserID,
+			Username:       a.Username,
+			UserDomainID:   a.UserDomainID,
+			UserDomainName: a.UserDomainName,
+			Passcode:       a.Passcode,
+		}, nil
+	case "multifactor":
+		if len(c.AuthMethods) == 0 {
+			return nil, errors.New("auth_type 'v3multifactor' requires auth_methods")
+		}
+		var factors MultiFactorAuth
+		for _, method := range c.AuthMethods {
+			if strings.TrimPrefix(strings.ToLower(strings.TrimSpace(method)), "v3") == "multifactor" {
+				return nil, errors.New("auth_methods must not contain 'v3multifactor'")
+			}
+			factor, err := c.authenticator(strings.TrimSpace(method), a)
+			if err != nil {
+				return nil, err
+			}
+			factors = append(factors, factor)
+		}
+		return factors, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth_type '%s'", authType)
+	}
+}
+
+// findConfigFile returns the first existing file out of the file
+// named by the given environment variable and the given file names
+// within the standard OpenStack configuration directories.
+func findConfigFile(envVar string, names ...string) (string, bool) {
+	if file := os.Getenv(envVar); file != "" {
+		_, err := os.Stat(file)
+		return file, err == nil
+	}
+
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", "openstack"))
+	}
+	dirs = append(dirs, "/etc/openstack")
+	for _, dir := range dirs {
+		for _, name := range names {
+			file := filepath.Join(dir, name)
+			if _, err := os.Stat(file); err == nil {
+				return file, true
+			}
+		}
+	}
+	return "", false
+}
+
+// readClouds returns the raw cloud entries of a clouds.yaml
+// or secure.yaml file.
+func readClouds(file string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var content struct {
+		Clouds map[string]interface{} `yaml:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return content.Clouds, nil
+}
+
+// mergeYAML merges src into dst recursively. Values
+// in src take precedence.
+func mergeYAML(dst, src interface{}) interface{} {
+	dstMap, ok1 := dst.(map[string]interface{})
+	srcMap, ok2 := src.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return src
+	}
+	merged := make(map[string]interface{}, len(dstMap)+len(srcMap))
+	for k, v := range dstMap {
+		merged[k] = v
+	}
+	for k, v := range srcMap {
+		if existing, ok := merged[k]; ok {
+			merged[k] = mergeYAML(existing, v)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
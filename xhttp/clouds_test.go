@@ -0,0 +1,306 @@
+package xhttp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadCloudConfig(t *testing.T) {
+	clearOSEnv(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "clouds.yaml"), cloudsYAML)
+	writeFile(t, filepath.Join(dir, "secure.yaml"), secureYAML)
+	t.Setenv("OS_CLIENT_CONFIG_FILE", filepath.Join(dir, "clouds.yaml"))
+	t.Setenv("OS_CLIENT_SECURE_FILE", filepath.Join(dir, "secure.yaml"))
+
+	for i, test := range loadCloudConfigTests {
+		t.Setenv("OS_CLOUD", test.Env)
+		config, err := LoadCloudConfig(test.Name)
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but succeeded", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to load cloud config: %v", i, err)
+		}
+		if err == nil && !reflect.DeepEqual(*config, test.Config) {
+			t.Fatalf("Test %d: got %+v - want %+v", i, *config, test.Config)
+		}
+	}
+}
+
+func TestLoadCloudConfigNotFound(t *testing.T) {
+	clearOSEnv(t)
+	t.Setenv("OS_CLIENT_CONFIG_FILE", filepath.Join(t.TempDir(), "clouds.yaml"))
+	if _, err := LoadCloudConfig("devstack"); err == nil {
+		t.Fatal("Loaded a cloud config from a non-existing file")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	for i, test := range configFromEnvTests {
+		clearOSEnv(t)
+		for key, value := range test.Env {
+			t.Setenv(key, value)
+		}
+
+		config, err := ConfigFromEnv()
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but succeeded", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to read config from environment: %v", i, err)
+		}
+		if err == nil && !reflect.DeepEqual(*config, test.Config) {
+			t.Fatalf("Test %d: got %+v - want %+v", i, *config, test.Config)
+		}
+	}
+}
+
+const cloudsYAML = `
+clouds:
+  devstack:
+    auth:
+      auth_url: https://keystone:5000/v3
+      username: admin
+      user_domain_name: Default
+      project_name: admin
+      project_domain_name: Default
+    region_name: RegionOne
+    interface: internal
+    cacert: /etc/ssl/ca.pem
+  default-domain:
+    auth:
+      auth_url: https://keystone:5000/v3
+      username: demo
+      password: secret
+      project_name: demo
+      default_domain: default
+    key_manager_endpoint_override: https://barbican:9311
+    verify: false
+  app-credential:
+    auth_type: v3applicationcredential
+    auth:
+      auth_url: https://keystone:5000/v3
+      application_credential_id: app-id
+      application_credential_secret: app-secret
+  multi-factor:
+    auth_type: v3multifactor
+    auth_methods:
+      - v3password
+      - v3totp
+    auth:
+      auth_url: https://keystone:5000/v3
+      user_id: user-id
+      password: secret
+      passcode: "123456"
+      system_scope: all
+  unsupported:
+    auth_type: v3oidcpassword
+    auth:
+      auth_url: https://keystone:5000/v3
+`
+
+const secureYAML = `
+clouds:
+  devstack:
+    auth:
+      password: devstack-secret
+`
+
+var loadCloudConfigTests = []struct {
+	Name       string
+	Env        string // OS_CLOUD
+	Config     Config
+	ShouldFail bool
+}{
+	{ // 0 - Password from secure.yaml
+		Name: "devstack",
+		Config: Config{
+			Region:    "RegionOne",
+			Interface: "internal",
+			TLS:       TLSConfig{CAFile: "/etc/ssl/ca.pem"},
+			Login: Credentials{
+				AuthUrl:       "https://keystone:5000/v3",
+				ProjectName:   "admin",
+				ProjectDomain: "Default",
+				Auth: PasswordAuth{
+					Username:       "admin",
+					UserDomainName: "Default",
+					Password:       "devstack-secret",
+				},
+			},
+		},
+	},
+	{ // 1 - Cloud from OS_CLOUD
+		Env: "default-domain",
+		Config: Config{
+			Endpoint: "https://barbican:9311",
+			TLS:      TLSConfig{InsecureSkipVerify: true},
+			Login: Credentials{
+				AuthUrl:         "https://keystone:5000/v3",
+				ProjectName:     "demo",
+				ProjectDomainID: "default",
+				Auth: PasswordAuth{
+					Username:     "demo",
+					UserDomainID: "default",
+					Password:     "secret",
+				},
+			},
+		},
+	},
+	{ // 2
+		Name: "app-credential",
+		Env:  "devstack",
+		Config: Config{
+			Login: Credentials{
+				AuthUrl: "https://keystone:5000/v3",
+				Auth: ApplicationCredentialAuth{
+					ID:     "app-id",
+					Secret: "app-secret",
+				},
+			},
+		},
+	},
+	{ // 3
+		Name: "multi-factor",
+		Config: Config{
+			Login: Credentials{
+				AuthUrl: "https://keystone:5000/v3",
+				System:  true,
+				Auth: MultiFactorAuth{
+					PasswordAuth{UserID: "user-id", Password: "secret"},
+					TOTPAuth{UserID: "user-id", Passcode: "123456"},
+				},
+			},
+		},
+	},
+	{Name: "unsupported", ShouldFail: true}, // 4
+	{Name: "missing", ShouldFail: true},     // 5
+	{ShouldFail: true},                      // 6 - No name and no OS_CLOUD
+}
+
+var configFromEnvTests = []struct {
+	Env        map[string]string
+	Config     Config
+	ShouldFail bool
+}{
+	{ // 0
+		Env: map[string]string{
+			"OS_AUTH_URL":            "https://keystone:5000/v3",
+			"OS_USERNAME":            "admin",
+			"OS_PASSWORD":            "secret",
+			"OS_USER_DOMAIN_NAME":    "Default",
+			"OS_PROJECT_NAME":        "admin",
+			"OS_PROJECT_DOMAIN_NAME": "Default",
+			"OS_REGION_NAME":         "RegionOne",
+			"OS_INTERFACE":           "public",
+		},
+		Config: Config{
+			Region:    "RegionOne",
+			Interface: "public",
+			Login: Credentials{
+				AuthUrl:       "https://keystone:5000/v3",
+				ProjectName:   "admin",
+				ProjectDomain: "Default",
+				Auth: PasswordAuth{
+					Username:       "admin",
+					UserDomainName: "Default",
+					Password:       "secret",
+				},
+			},
+		},
+	},
+	{ // 1 - Legacy variables and OS_INSECURE
+		Env: map[string]string{
+			"OS_AUTH_URL":       "https://keystone:5000/v3",
+			"OS_USERNAME":       "demo",
+			"OS_PASSWORD":       "secret",
+			"OS_TENANT_NAME":    "demo",
+			"OS_DEFAULT_DOMAIN": "default",
+			"OS_ENDPOINT_TYPE":  "internalURL",
+			"OS_INSECURE":       "true",
+		},
+		Config: Config{
+			Interface: "internalURL",
+			TLS:       TLSConfig{InsecureSkipVerify: true},
+			Login: Credentials{
+				AuthUrl:         "https://keystone:5000/v3",
+				ProjectName:     "demo",
+				ProjectDomainID: "default",
+				Auth: PasswordAuth{
+					Username:     "demo",
+					UserDomainID: "default",
+					Password:     "secret",
+				},
+			},
+		},
+	},
+	{ // 2
+		Env: map[string]string{
+			"OS_AUTH_URL":                      "https://keystone:5000/v3",
+			"OS_AUTH_TYPE":                     "token",
+			"OS_TOKEN":                         "gAAAAAB",
+			"OS_PROJECT_ID":                    "project-id",
+			"OS_KEY_MANAGER_ENDPOINT_OVERRIDE": "https://barbican:9311",
+			"OS_INSECURE":                      "false",
+		},
+		Config: Config{
+			Endpoint: "https://barbican:9311",
+			Login: Credentials{
+				AuthUrl:   "https://keystone:5000/v3",
+				ProjectID: "project-id",
+				Auth:      TokenAuth{Token: "gAAAAAB"},
+			},
+		},
+	},
+	{ // 3 - No OS_AUTH_URL
+		Env: map[string]string{
+			"OS_USERNAME": "admin",
+			"OS_PASSWORD": "secret",
+		},
+		ShouldFail: true,
+	},
+	{ // 4
+		Env: map[string]string{
+			"OS_AUTH_URL": "https://keystone:5000/v3",
+			"OS_INSECURE": "maybe",
+		},
+		ShouldFail: true,
+	},
+	{ // 5
+		Env: map[string]string{
+			"OS_AUTH_URL":     "https://keystone:5000/v3",
+			"OS_SYSTEM_SCOPE": "region",
+		},
+		ShouldFail: true,
+	},
+}
+
+// clearOSEnv unsets all OS_* environment variables
+// for the duration of the test.
+func clearOSEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"OS_CLOUD", "OS_CLIENT_CONFIG_FILE", "OS_CLIENT_SECURE_FILE",
+		"OS_AUTH_TYPE", "OS_AUTH_METHODS", "OS_REGION_NAME", "OS_INTERFACE", "OS_ENDPOINT_TYPE",
+		"OS_KEY_MANAGER_ENDPOINT_OVERRIDE", "OS_KEY_MANAGER_SERVICE_TYPE", "OS_KEY_MANAGER_SERVICE_NAME",
+		"OS_CACERT", "OS_CERT", "OS_KEY", "OS_INSECURE",
+		"OS_AUTH_URL", "OS_USERNAME", "OS_USER_ID", "OS_PASSWORD", "OS_USER_DOMAIN_NAME", "OS_USER_DOMAIN_ID",
+		"OS_PROJECT_NAME", "OS_TENANT_NAME", "OS_PROJECT_ID", "OS_TENANT_ID",
+		"OS_PROJECT_DOMAIN_NAME", "OS_PROJECT_DOMAIN_ID", "OS_DOMAIN_NAME", "OS_DOMAIN_ID",
+		"OS_DEFAULT_DOMAIN", "OS_SYSTEM_SCOPE", "OS_TOKEN",
+		"OS_APPLICATION_CREDENTIAL_ID", "OS_APPLICATION_CREDENTIAL_NAME", "OS_APPLICATION_CREDENTIAL_SECRET",
+		"OS_PASSCODE",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write '%s': %v", name, err)
+	}
+}
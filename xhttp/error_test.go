@@ -0,0 +1,122 @@
+package xhttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestParseErrorResponse(t *testing.T) {
+	reqURL, _ := url.Parse("http://localhost:9311/v1/secrets")
+	for i, test := range parseErrorResponseTests {
+		resp := &http.Response{
+			StatusCode:    test.Status,
+			ContentLength: -1,
+			Status:        http.StatusText(test.Status),
+			Header:        http.Header{},
+			Body:          io.NopCloser(strings.NewReader(test.Body)),
+			Request:       &http.Request{Method: http.MethodPost, URL: reqURL},
+		}
+		if test.ContentType != "" {
+			resp.Header.Set("Content-Type", test.ContentType)
+		}
+		for key, value := range test.Header {
+			resp.Header.Set(key, value)
+		}
+
+		err := parseErrorResponse(resp)
+		if test.Status < 400 {
+			if err != nil {
+				t.Fatalf("Test %d: got error '%v' for status %d", i, err, test.Status)
+			}
+			continue
+		}
+
+		var e xerror.Error
+		if !errors.As(err, &e) {
+			t.Fatalf("Test %d: got error '%v' of type %T - want %T", i, err, err, e)
+		}
+		if e.Status() != test.Status || e.Title() != test.Title || e.Message() != test.Message {
+			t.Fatalf("Test %d: got %d '%s' '%s' - want %d '%s' '%s'", i, e.Status(), e.Title(), e.Message(), test.Status, test.Title, test.Message)
+		}
+		if e.RequestID() != test.RequestID {
+			t.Fatalf("Test %d: got request ID '%s' - want '%s'", i, e.RequestID(), test.RequestID)
+		}
+		if e.Method() != http.MethodPost || e.URL() != reqURL.String() {
+			t.Fatalf("Test %d: got request '%s %s' - want '%s %s'", i, e.Method(), e.URL(), http.MethodPost, reqURL)
+		}
+		if !errors.Is(err, test.Kind) && test.Kind != 0 {
+			t.Fatalf("Test %d: got error '%v' - want kind '%v'", i, err, test.Kind)
+		}
+	}
+}
+
+var parseErrorResponseTests = []struct {
+	Status      int
+	ContentType string
+	Header      map[string]string
+	Body        string
+
+	Title     string
+	Message   string
+	RequestID string
+	Kind      xerror.Kind
+}{
+	{Status: http.StatusCreated}, // 0
+	{ // 1 - Barbican fault
+		Status:      http.StatusNotFound,
+		ContentType: "application/json; charset=UTF-8",
+		Header:      map[string]string{"X-Openstack-Request-Id": "req-1"},
+		Body:        `{"code": 404, "title": "Not Found", "description": "Not Found. Sorry but your secret is in another castle."}`,
+		Title:       "Not Found",
+		Message:     "Not Found. Sorry but your secret is in another castle.",
+		RequestID:   "req-1",
+		Kind:        xerror.NotFound,
+	},
+	{ // 2 - Barbican quota fault
+		Status:      http.StatusForbidden,
+		ContentType: "application/json",
+		Header:      map[string]string{"X-Openstack-Request-Id": "req-2", "X-Compute-Request-Id": "req-other"},
+		Body:        `{"code": 403, "title": "Forbidden", "description": "Quota reached for project 6f0b1f2e. Only 10 secrets are allowed."}`,
+		Title:       "Forbidden",
+		Message:     "Quota reached for project 6f0b1f2e. Only 10 secrets are allowed.",
+		RequestID:   "req-2",
+		Kind:        xerror.QuotaExceeded,
+	},
+	{ // 3 - Keystone error
+		Status:      http.StatusUnauthorized,
+		ContentType: "application/json",
+		Header:      map[string]string{"X-Compute-Request-Id": "req-3"},
+		Body:        `{"error": {"code": 401, "title": "Unauthorized", "message": "The request you have made requires authentication."}}`,
+		Title:       "Unauthorized",
+		Message:     "The request you have made requires authentication.",
+		RequestID:   "req-3",
+		Kind:        xerror.Unauthorized,
+	},
+	{ // 4 - Keystone error without quota fault
+		Status:      http.StatusForbidden,
+		ContentType: "application/json",
+		Body:        `{"error": {"code": 403, "title": "Forbidden", "message": "You are not authorized to perform the requested action: identity:get_quota."}}`,
+		Title:       "Forbidden",
+		Message:     "You are not authorized to perform the requested action: identity:get_quota.",
+		Kind:        xerror.Forbidden,
+	},
+	{ // 5 - Non-JSON body
+		Status:      http.StatusServiceUnavailable,
+		ContentType: "text/html",
+		Body:        "  <html>Service Unavailable</html>\n",
+		Message:     "<html>Service Unavailable</html>",
+	},
+	{ // 6 - JSON without fault
+		Status:      http.StatusTooManyRequests,
+		ContentType: "application/json",
+		Body:        `{}`,
+		Message:     "{}",
+		Kind:        xerror.RateLimited,
+	},
+}
@@ -0,0 +1,181 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default backoff bounds used unless configured
+// otherwise via RetryPolicy.
+const (
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// RetryPolicy controls how requests failing with transient
+// errors are retried. Transient errors are network errors and
+// the status codes 429 (Too Many Requests), 502 (Bad Gateway),
+// 503 (Service Unavailable) and 504 (Gateway Timeout).
+//
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per
+	// request, including the first one. Values < 2 disable
+	// retries.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff
+	// between two attempts. The backoff doubles with every
+	// attempt and is randomized by up to 50%. A Retry-After
+	// response header takes precedence over the backoff but is
+	// capped at MaxBackoff. Canceling the request context ends
+	// the wait immediately.
+	MinBackoff time.Duration // Defaults to DefaultMinBackoff.
+	MaxBackoff time.Duration // Defaults to DefaultMaxBackoff.
+
+	// RetryNonIdempotent enables retries of POST and PATCH
+	// requests. Such a request may have been processed even
+	// though it failed, e.g. when the response got lost, such
+	// that retrying it may create a resource twice.
+	//
+	// Keystone token requests are always retried since obtaining
+	// another token has no side effects.
+	RetryNonIdempotent bool
+
+	// OnRetry, if set, is called before waiting for
+	// the next attempt.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is
+// going to be retried.
+type RetryEvent struct {
+	Method  string
+	URL     string
+	Attempt int           // The failed attempt, starting at 1.
+	Status  int           // The response status code, or 0 on network errors.
+	Err     error         // The network error, if any.
+	Delay   time.Duration // The time until the next attempt.
+}
+
+// do sends the request returned by newRequest and retries it
+// according to the policy. The request is rebuilt for every
+// attempt. Retries are only performed if retryable is true.
+func (p *RetryPolicy) do(ctx context.Context, client *http.Client, retryable bool, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if attempt >= p.MaxAttempts || !retryable || !isTransient(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := p.backoff(attempt)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			if d, ok := retryAfter(resp); ok {
+				delay = d
+				if limit := p.maxBackoff(); delay > limit {
+					delay = limit
+				}
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096)) // Allow connection reuse
+			resp.Body.Close()
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(RetryEvent{
+				Method:  req.Method,
+				URL:     req.URL.String(),
+				Attempt: attempt,
+				Status:  status,
+				Err:     err,
+				Delay:   delay,
+			})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, context.Cause(ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the randomized backoff after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	low, high := p.MinBackoff, p.maxBackoff()
+	if low <= 0 {
+		low = DefaultMinBackoff
+	}
+	d := low
+	for i := 1; i < attempt && d < high; i++ {
+		d *= 2
+	}
+	if d > high {
+		d = high
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// maxBackoff returns the upper bound of the delay between
+// two attempts.
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// isIdempotent reports whether requests with
+// the given method can be retried safely.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransient reports whether a request that returned
+// resp and err failed with a transient error.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the delay requested by the Retry-After
+// header of resp, either in seconds or as HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
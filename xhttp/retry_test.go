@@ -0,0 +1,247 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyHandler fails the first n requests with the given
+// status code and Retry-After header, if not empty.
+func flakyHandler(n, status int, retryAfter string) (http.HandlerFunc, func() int) {
+	var (
+		lock     sync.Mutex
+		requests int
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		fail := requests <= n
+		lock.Unlock()
+
+		if fail {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("ok"))
+	}
+	count := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return requests
+	}
+	return handler, count
+}
+
+func TestRetryTransient(t *testing.T) {
+	for i, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		server := newTestServer(t)
+		handler, requests := flakyHandler(2, status, "")
+		server.resource = handler
+
+		var events []RetryEvent
+		config := server.config()
+		config.Retry = RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+			OnRetry:     func(e RetryEvent) { events = append(events, e) },
+		}
+		client := newTestClient(t, server, config)
+
+		if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+			t.Fatalf("Test %d: request failed: %v", i, err)
+		}
+		if n := requests(); n != 3 {
+			t.Fatalf("Test %d: got %d requests - want 3", i, n)
+		}
+		if len(events) != 2 {
+			t.Fatalf("Test %d: got %d retry events - want 2", i, len(events))
+		}
+		for j, e := range events {
+			if e.Attempt != j+1 || e.Status != status || e.Method != http.MethodGet {
+				t.Fatalf("Test %d: got retry event %+v - want attempt %d with status %d", i, e, j+1, status)
+			}
+		}
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	server := newTestServer(t)
+	handler, requests := flakyHandler(5, http.StatusServiceUnavailable, "")
+	server.resource = handler
+
+	config := server.config()
+	config.Retry = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	client := newTestClient(t, server, config)
+
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err == nil {
+		t.Fatal("Request succeeded despite exceeding the max. number of attempts")
+	}
+	if n := requests(); n != 3 {
+		t.Fatalf("Got %d requests - want 3", n)
+	}
+}
+
+func TestRetryNonTransient(t *testing.T) {
+	server := newTestServer(t)
+	handler, requests := flakyHandler(1, http.StatusInternalServerError, "")
+	server.resource = handler
+
+	config := server.config()
+	config.Retry = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	client := newTestClient(t, server, config)
+
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err == nil {
+		t.Fatal("Request succeeded despite a non-transient error")
+	}
+	if n := requests(); n != 1 {
+		t.Fatalf("Got %d requests - want 1", n)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	for i, test := range retryNonIdempotentTests {
+		server := newTestServer(t)
+		handler, requests := flakyHandler(1, http.StatusServiceUnavailable, "")
+		server.resource = handler
+
+		config := server.config()
+		config.Retry = RetryPolicy{
+			MaxAttempts:        3,
+			MinBackoff:         time.Millisecond,
+			RetryNonIdempotent: test.RetryNonIdempotent,
+		}
+		client := newTestClient(t, server, config)
+
+		_, err := client.HttpPost(context.Background(), server.URL+"/v1/secrets", []byte("{}"), nil)
+		if test.Requests == 1 && err == nil {
+			t.Fatalf("Test %d: POST request succeeded despite not being retried", i)
+		}
+		if test.Requests > 1 && err != nil {
+			t.Fatalf("Test %d: POST request failed: %v", i, err)
+		}
+		if n := requests(); n != test.Requests {
+			t.Fatalf("Test %d: got %d requests - want %d", i, n, test.Requests)
+		}
+	}
+}
+
+var retryNonIdempotentTests = []struct {
+	RetryNonIdempotent bool
+	Requests           int
+}{
+	{RetryNonIdempotent: false, Requests: 1},
+	{RetryNonIdempotent: true, Requests: 2},
+}
+
+func TestRetryAfter(t *testing.T) {
+	server := newTestServer(t)
+	handler, _ := flakyHandler(1, http.StatusTooManyRequests, "3600")
+	server.resource = handler
+
+	const MaxBackoff = 20 * time.Millisecond
+	var delay time.Duration
+	config := server.config()
+	config.Retry = RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  MaxBackoff,
+		OnRetry:     func(e RetryEvent) { delay = e.Delay },
+	}
+	client := newTestClient(t, server, config)
+
+	start := time.Now()
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if delay != MaxBackoff {
+		t.Fatalf("Got retry delay %v - want Retry-After capped to %v", delay, MaxBackoff)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Request took %v despite a max. backoff of %v", elapsed, MaxBackoff)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	server := newTestServer(t)
+	handler, requests := flakyHandler(1, http.StatusServiceUnavailable, "3600")
+	server.resource = handler
+
+	config := server.config()
+	config.Retry = RetryPolicy{
+		MaxAttempts: 2,
+		MinBackoff:  time.Hour,
+		MaxBackoff:  time.Hour,
+	}
+	client := newTestClient(t, server, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.HttpGet(ctx, server.URL+"/v1/secrets", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Got error '%v' - want '%v'", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Canceling the request took %v", elapsed)
+	}
+	if n := requests(); n != 1 {
+		t.Fatalf("Got %d requests - want 1", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for i, test := range backoffTests {
+		for j := 0; j < 100; j++ {
+			if d := policy.backoff(test.Attempt); d < test.Low || d > test.High {
+				t.Fatalf("Test %d: got backoff %v for attempt %d - want %v - %v", i, d, test.Attempt, test.Low, test.High)
+			}
+		}
+	}
+}
+
+var backoffTests = []struct {
+	Attempt   int
+	Low, High time.Duration
+}{
+	{Attempt: 1, Low: 5 * time.Millisecond, High: 10 * time.Millisecond},
+	{Attempt: 2, Low: 10 * time.Millisecond, High: 20 * time.Millisecond},
+	{Attempt: 3, Low: 20 * time.Millisecond, High: 40 * time.Millisecond},
+	{Attempt: 4, Low: 25 * time.Millisecond, High: 50 * time.Millisecond},
+	{Attempt: 100, Low: 25 * time.Millisecond, High: 50 * time.Millisecond},
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	for i, test := range retryAfterTests {
+		resp := &http.Response{Header: http.Header{}}
+		if test.Value != "" {
+			resp.Header.Set("Retry-After", test.Value)
+		}
+		d, ok := retryAfter(resp)
+		if ok != test.OK || d != test.Delay {
+			t.Fatalf("Test %d: got %v, %v - want %v, %v", i, d, ok, test.Delay, test.OK)
+		}
+	}
+}
+
+var retryAfterTests = []struct {
+	Value string
+	Delay time.Duration
+	OK    bool
+}{
+	{Value: "", OK: false},
+	{Value: "0", Delay: 0, OK: true},
+	{Value: "120", Delay: 2 * time.Minute, OK: true},
+	{Value: "-1", OK: false},
+	{Value: "Wed, 21 Oct 2015 07:28:00 GMT", Delay: 0, OK: true}, // In the past
+	{Value: "soon", OK: false},
+}
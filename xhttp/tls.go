@@ -0,0 +1,96 @@
+package xhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig contains the TLS options used when connecting
+// to Keystone and Barbican. The zero value uses the system
+// root CAs and Go's default TLS settings.
+type TLSConfig struct {
+	// CAFile is the path of a PEM-encoded CA bundle. If CAFile
+	// or CAPEM is set, server certificates are verified against
+	// these CAs only - not against the system root CAs.
+	CAFile string
+
+	// CAPEM contains PEM-encoded CA certificates. It can be
+	// combined with CAFile.
+	CAPEM []byte
+
+	// CertFile and KeyFile are the paths of a PEM-encoded client
+	// certificate and private key used for mutual TLS.
+	CertFile string
+	KeyFile  string
+
+	// CertPEM and KeyPEM contain a PEM-encoded client certificate
+	// and private key. Alternative to CertFile and KeyFile.
+	CertPEM []byte
+	KeyPEM  []byte
+
+	// ServerName overrides the host name used to verify the
+	// server certificates, e.g. when connecting via an IP.
+	ServerName string
+
+	// MinVersion is the minimum TLS version, e.g. tls.VersionTLS13.
+	// Defaults to tls.VersionTLS12.
+	MinVersion uint16
+
+	// InsecureSkipVerify disables the verification of server
+	// certificates. It should only be used for testing.
+	InsecureSkipVerify bool
+}
+
+// Config returns the crypto/tls configuration described by c.
+func (c *TLSConfig) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		MinVersion:         c.MinVersion,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if c.CAFile != "" || len(c.CAPEM) > 0 {
+		pool := x509.NewCertPool()
+		if c.CAFile != "" {
+			pem, err := os.ReadFile(c.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("tls: failed to read CA file: %v", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("tls: no CA certificates found in '%s'", c.CAFile)
+			}
+		}
+		if len(c.CAPEM) > 0 && !pool.AppendCertsFromPEM(c.CAPEM) {
+			return nil, errors.New("tls: no CA certificates found in CA PEM")
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case c.CertFile != "" || c.KeyFile != "":
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("tls: client certificate and private key files must be set together")
+		}
+		if len(c.CertPEM) > 0 || len(c.KeyPEM) > 0 {
+			return nil, errors.New("tls: client certificate files and PEM are mutually exclusive")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to load client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case len(c.CertPEM) > 0 || len(c.KeyPEM) > 0:
+		cert, err := tls.X509KeyPair(c.CertPEM, c.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("tls: invalid client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,153 @@
+package xhttp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTLSConfig(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "cert.pem"), string(certPEM))
+	writeFile(t, filepath.Join(dir, "key.pem"), string(keyPEM))
+	writeFile(t, filepath.Join(dir, "empty.pem"), "")
+
+	for i, test := range tlsConfigTests {
+		c := test.Config
+		c.CAFile = resolve(dir, c.CAFile)
+		c.CertFile = resolve(dir, c.CertFile)
+		c.KeyFile = resolve(dir, c.KeyFile)
+		if test.PEM {
+			c.CAPEM, c.CertPEM, c.KeyPEM = certPEM, certPEM, keyPEM
+		}
+
+		config, err := c.Config()
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but succeeded", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: failed to create TLS config: %v", i, err)
+		}
+		if err != nil {
+			continue
+		}
+		if config.MinVersion != test.MinVersion {
+			t.Fatalf("Test %d: got min. version %x - want %x", i, config.MinVersion, test.MinVersion)
+		}
+		if (config.RootCAs != nil) != test.RootCAs {
+			t.Fatalf("Test %d: got custom root CAs: %v - want %v", i, config.RootCAs != nil, test.RootCAs)
+		}
+		if len(config.Certificates) != test.Certificates {
+			t.Fatalf("Test %d: got %d client certificates - want %d", i, len(config.Certificates), test.Certificates)
+		}
+	}
+}
+
+func TestNewClientTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Failed handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	for i, test := range []struct {
+		TLS        TLSConfig
+		ShouldFail bool
+	}{
+		{TLS: TLSConfig{}, ShouldFail: true},                                     // 0 - Not signed by a system root CA
+		{TLS: TLSConfig{CAPEM: caPEM}},                                           // 1
+		{TLS: TLSConfig{InsecureSkipVerify: true}},                               // 2
+		{TLS: TLSConfig{CAPEM: caPEM, ServerName: "barbican"}, ShouldFail: true}, // 3 - Wrong host name
+	} {
+		client, err := NewClient(Config{TLS: test.TLS})
+		if err != nil {
+			t.Fatalf("Test %d: failed to create client: %v", i, err)
+		}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if err == nil && test.ShouldFail {
+			t.Fatalf("Test %d: should fail but succeeded", i)
+		}
+		if err != nil && !test.ShouldFail {
+			t.Fatalf("Test %d: request failed: %v", i, err)
+		}
+	}
+}
+
+// resolve returns the path of the named file in dir,
+// or the empty string if name is empty.
+func resolve(dir, name string) string {
+	if name == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+// newTestCertificate returns a new PEM-encoded self-signed
+// certificate and its private key.
+func newTestCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+var tlsConfigTests = []struct {
+	Config TLSConfig
+	PEM    bool // Sets CAPEM, CertPEM and KeyPEM
+
+	MinVersion   uint16
+	RootCAs      bool
+	Certificates int
+	ShouldFail   bool
+}{
+	{Config: TLSConfig{}, MinVersion: tls.VersionTLS12},                                                          // 0
+	{Config: TLSConfig{MinVersion: tls.VersionTLS13}, MinVersion: tls.VersionTLS13},                              // 1
+	{Config: TLSConfig{CAFile: "cert.pem"}, MinVersion: tls.VersionTLS12, RootCAs: true},                         // 2
+	{Config: TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}, MinVersion: tls.VersionTLS12, Certificates: 1}, // 3
+	{PEM: true, MinVersion: tls.VersionTLS12, RootCAs: true, Certificates: 1},                                    // 4
+	{Config: TLSConfig{CAFile: "missing.pem"}, ShouldFail: true},                                                 // 5
+	{Config: TLSConfig{CAFile: "empty.pem"}, ShouldFail: true},                                                   // 6
+	{Config: TLSConfig{CAPEM: []byte("not a certificate")}, ShouldFail: true},                                    // 7
+	{Config: TLSConfig{CertFile: "cert.pem"}, ShouldFail: true},                                                  // 8 - No private key
+	{Config: TLSConfig{CertFile: "cert.pem", KeyFile: "empty.pem"}, ShouldFail: true},                            // 9
+	{Config: TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}, PEM: true, ShouldFail: true},                   // 10 - Files and PEM
+	{Config: TLSConfig{CertPEM: []byte("not a certificate")}, ShouldFail: true},                                  // 11
+}
@@ -0,0 +1,94 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+// DefaultTokenRefreshSkew is how long before its expiry
+// a token is refreshed unless configured otherwise.
+const DefaultTokenRefreshSkew = 5 * time.Minute
+
+// refresh is an in-flight token refresh shared
+// by all concurrent callers.
+type refresh struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshAt returns when a token obtained now and expiring
+// at expiry should be refreshed. The skew is capped to half
+// of the token lifetime such that short-lived tokens are not
+// refreshed on every request.
+func refreshAt(expiry time.Time, skew time.Duration) time.Time {
+	if skew <= 0 {
+		skew = DefaultTokenRefreshSkew
+	}
+	if lifetime := time.Until(expiry); skew > lifetime/2 {
+		skew = lifetime / 2
+	}
+	return expiry.Add(-skew)
+}
+
+// authToken returns a token for authenticating a request.
+//
+// The current token is returned unless it is due for refresh
+// or equal to rejected - a token the server has just rejected.
+// Otherwise, the token is refreshed. Concurrent callers share
+// a single in-flight refresh. If a due refresh fails while the
+// current token has not yet expired, the current token is used.
+func (c *Client) authToken(ctx context.Context, rejected string) (string, error) {
+	for {
+		c.lock.Lock()
+		if c.closed {
+			c.lock.Unlock()
+			return "", xerror.ErrClosed
+		}
+		token := c.token
+		if token.Key != "" && token.Key != rejected && time.Now().Before(token.RefreshAt) {
+			c.lock.Unlock()
+			return token.Key, nil
+		}
+
+		r, leader := c.refresh, false
+		if r == nil {
+			r, leader = &refresh{done: make(chan struct{})}, true
+			c.refresh = r
+		}
+		config := c.config
+		c.lock.Unlock()
+
+		if leader {
+			r.err = c.Authenticate(ctx, config)
+			c.lock.Lock()
+			c.refresh = nil
+			c.lock.Unlock()
+			close(r.done)
+		} else {
+			select {
+			case <-r.done:
+			case <-ctx.Done():
+				return "", context.Cause(ctx)
+			}
+		}
+
+		if r.err == nil {
+			c.lock.Lock()
+			key := c.token.Key
+			c.lock.Unlock()
+			return key, nil
+		}
+		// The refresh may have failed because the context of the
+		// leading caller was canceled. Then, try again ourselves.
+		if !leader && ctx.Err() == nil && (errors.Is(r.err, context.Canceled) || errors.Is(r.err, context.DeadlineExceeded)) {
+			continue
+		}
+		if token.Key != "" && token.Key != rejected && time.Now().Before(token.Expiry) {
+			return token.Key, nil
+		}
+		return "", r.err
+	}
+}
@@ -0,0 +1,170 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artashesbalabekyan/barbican-sdk-go/xerror"
+)
+
+func TestRefreshAt(t *testing.T) {
+	for i, test := range refreshAtTests {
+		expiry := time.Now().Add(test.Lifetime)
+		refresh := refreshAt(expiry, test.Skew)
+
+		// The lifetime shrinks slightly between time.Now and
+		// time.Until within refreshAt. Allow some tolerance.
+		if diff := expiry.Sub(refresh) - test.Before; diff < -time.Second || diff > time.Second {
+			t.Fatalf("Test %d: token is refreshed %v before its expiry - want %v", i, expiry.Sub(refresh), test.Before)
+		}
+	}
+}
+
+var refreshAtTests = []struct {
+	Lifetime time.Duration
+	Skew     time.Duration
+	Before   time.Duration
+}{
+	{Lifetime: time.Hour, Skew: 0, Before: DefaultTokenRefreshSkew},
+	{Lifetime: time.Hour, Skew: -time.Minute, Before: DefaultTokenRefreshSkew},
+	{Lifetime: time.Hour, Skew: 10 * time.Minute, Before: 10 * time.Minute},
+	{Lifetime: 4 * time.Minute, Skew: 0, Before: 2 * time.Minute},
+	{Lifetime: time.Hour, Skew: 2 * time.Hour, Before: 30 * time.Minute},
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	server := newTestServer(t)
+	client := newTestClient(t, server, server.config())
+
+	// Make the token due for refresh and slow down
+	// Keystone such that all requests wait for the
+	// same refresh.
+	client.lock.Lock()
+	client.token.RefreshAt = time.Now().Add(-time.Second)
+	client.lock.Unlock()
+	server.lock.Lock()
+	server.authDelay = 100 * time.Millisecond
+	server.lock.Unlock()
+
+	const N = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, N)
+	for i := 0; i < N; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if n := server.authCount(); n != 2 {
+		t.Fatalf("Got %d token requests - want 2: one login and one shared refresh", n)
+	}
+}
+
+func TestRefreshFailure(t *testing.T) {
+	server := newTestServer(t)
+	client := newTestClient(t, server, server.config())
+
+	client.lock.Lock()
+	client.token.RefreshAt = time.Now().Add(-time.Second)
+	client.lock.Unlock()
+	server.lock.Lock()
+	server.authFail = true
+	server.lock.Unlock()
+
+	// The refresh fails but the current token has not yet
+	// expired. So, it should still be used.
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	// Once the token is rejected, the failed refresh
+	// must be reported.
+	server.invalidate("token-1")
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err == nil {
+		t.Fatal("Request succeeded with a rejected token")
+	}
+}
+
+func TestReauthenticateOnUnauthorized(t *testing.T) {
+	server := newTestServer(t)
+	client := newTestClient(t, server, server.config())
+
+	// Revoke the token behind the client's back. The
+	// first attempt fails with 401 Unauthorized and the
+	// client has to re-authenticate.
+	server.invalidate("token-1")
+	resp, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if string(resp) != "ok" {
+		t.Fatalf("Got response '%s' - want 'ok'", resp)
+	}
+	if n := server.authCount(); n != 2 {
+		t.Fatalf("Got %d token requests - want 2", n)
+	}
+
+	// A request with a fresh token that still gets
+	// rejected must not loop.
+	server.lock.Lock()
+	server.tokens = map[string]bool{}
+	server.authFail = true
+	server.lock.Unlock()
+
+	_, err = client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil)
+	if err == nil {
+		t.Fatal("Request succeeded without a valid token")
+	}
+	if n := server.authCount(); n != 3 {
+		t.Fatalf("Got %d token requests - want 3", n)
+	}
+}
+
+func TestClose(t *testing.T) {
+	server := newTestServer(t)
+	config := server.config()
+	client := newTestClient(t, server, config)
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	server.lock.Lock()
+	revoked := append([]string(nil), server.revoked...)
+	server.lock.Unlock()
+	if len(revoked) != 1 || revoked[0] != "token-1" {
+		t.Fatalf("Got revoked tokens %v - want [token-1]", revoked)
+	}
+
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); !errors.Is(err, xerror.ErrClosed) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrClosed)
+	}
+	if _, err := client.HttpPost(context.Background(), server.URL+"/v1/secrets", nil, nil); !errors.Is(err, xerror.ErrClosed) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrClosed)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Closing a closed client failed: %v", err)
+	}
+
+	// A token obtained after closing must
+	// not be kept but revoked right away.
+	if err := client.Authenticate(context.Background(), config); !errors.Is(err, xerror.ErrClosed) {
+		t.Fatalf("Got error '%v' - want '%v'", err, xerror.ErrClosed)
+	}
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if len(server.tokens) != 0 {
+		t.Fatalf("Got %d valid tokens after closing - want 0", len(server.tokens))
+	}
+}
@@ -0,0 +1,111 @@
+package xhttp
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Default transport settings used unless
+// configured otherwise via TransportConfig.
+const (
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultKeepAlive             = 10 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultIdleConnTimeout       = 30 * time.Second
+	DefaultExpectContinueTimeout = 1 * time.Second
+	DefaultMaxIdleConns          = 100
+)
+
+// TransportConfig contains the options of the HTTP transport
+// used to connect to Keystone and Barbican. Zero values are
+// replaced by the corresponding defaults.
+type TransportConfig struct {
+	// RoundTripper, if set, is used to send requests instead of
+	// a transport built from the other fields and Config.TLS,
+	// e.g. an instrumented transport. Timeout still applies.
+	RoundTripper http.RoundTripper
+
+	// Timeout limits the total time of a single request,
+	// including reading the response body. Zero means no
+	// timeout besides the request context.
+	Timeout time.Duration
+
+	// Proxy returns the proxy for a given request.
+	// Defaults to http.ProxyFromEnvironment.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	DialTimeout           time.Duration // Defaults to DefaultDialTimeout.
+	KeepAlive             time.Duration // Defaults to DefaultKeepAlive.
+	TLSHandshakeTimeout   time.Duration // Defaults to DefaultTLSHandshakeTimeout.
+	IdleConnTimeout       time.Duration // Defaults to DefaultIdleConnTimeout.
+	ResponseHeaderTimeout time.Duration // Zero means no timeout.
+	ExpectContinueTimeout time.Duration // Defaults to DefaultExpectContinueTimeout.
+
+	MaxIdleConns        int // Defaults to DefaultMaxIdleConns.
+	MaxIdleConnsPerHost int // Defaults to http.DefaultMaxIdleConnsPerHost.
+	MaxConnsPerHost     int // Zero means no limit.
+}
+
+// NewClient returns a new client that sends requests via
+// config.HTTPClient, if set, or otherwise via a transport
+// built from config.Transport and config.TLS.
+//
+// The returned client is not yet authenticated.
+func NewClient(config Config) (*Client, error) {
+	if config.HTTPClient != nil {
+		return &Client{Client: *config.HTTPClient}, nil
+	}
+
+	t := config.Transport
+	client := &Client{
+		Client: http.Client{
+			Transport: t.RoundTripper,
+			Timeout:   t.Timeout,
+		},
+	}
+	if t.RoundTripper != nil {
+		return client, nil
+	}
+
+	tlsConfig, err := config.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+	proxy := t.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	client.Transport = &http.Transport{
+		TLSClientConfig: tlsConfig,
+		Proxy:           proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   durationOr(t.DialTimeout, DefaultDialTimeout),
+			KeepAlive: durationOr(t.KeepAlive, DefaultKeepAlive),
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          intOr(t.MaxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost:   intOr(t.MaxIdleConnsPerHost, http.DefaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       t.MaxConnsPerHost,
+		IdleConnTimeout:       durationOr(t.IdleConnTimeout, DefaultIdleConnTimeout),
+		TLSHandshakeTimeout:   durationOr(t.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: t.ResponseHeaderTimeout,
+		ExpectContinueTimeout: durationOr(t.ExpectContinueTimeout, DefaultExpectContinueTimeout),
+	}
+	return client, nil
+}
+
+func durationOr(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+func intOr(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
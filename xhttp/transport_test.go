@@ -0,0 +1,94 @@
+package xhttp
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTransport counts the requests sent
+// via the wrapped http.RoundTripper.
+type countingTransport struct {
+	http.RoundTripper
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func TestNewClientRoundTripper(t *testing.T) {
+	server := newTestServer(t)
+	transport := &countingTransport{RoundTripper: http.DefaultTransport}
+
+	config := server.config()
+	config.Transport = TransportConfig{RoundTripper: transport, Timeout: time.Minute}
+	config.TLS = TLSConfig{CAFile: "missing.pem"} // Ignored with a custom RoundTripper
+
+	client := newTestClient(t, server, config)
+	if client.Timeout != time.Minute {
+		t.Fatalf("Got timeout %v - want %v", client.Timeout, time.Minute)
+	}
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if n := transport.requests.Load(); n != 2 {
+		t.Fatalf("Got %d requests via the custom transport - want 2", n)
+	}
+}
+
+func TestNewClientHTTPClient(t *testing.T) {
+	server := newTestServer(t)
+	transport := &countingTransport{RoundTripper: http.DefaultTransport}
+
+	config := server.config()
+	config.HTTPClient = &http.Client{Transport: transport}
+	config.Transport = TransportConfig{Timeout: time.Minute} // Ignored with a custom HTTPClient
+	config.TLS = TLSConfig{CAFile: "missing.pem"}
+
+	client := newTestClient(t, server, config)
+	if client.Timeout != 0 {
+		t.Fatalf("Got timeout %v - want none", client.Timeout)
+	}
+	if _, err := client.HttpGet(context.Background(), server.URL+"/v1/secrets", nil); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if n := transport.requests.Load(); n != 2 {
+		t.Fatalf("Got %d requests via the custom client - want 2", n)
+	}
+}
+
+func TestNewClientTransport(t *testing.T) {
+	client, err := NewClient(Config{Transport: TransportConfig{
+		Timeout:             time.Minute,
+		DialTimeout:         time.Second,
+		MaxIdleConnsPerHost: 8,
+		MaxConnsPerHost:     16,
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Got transport %T - want %T", client.Transport, transport)
+	}
+	if client.Timeout != time.Minute {
+		t.Fatalf("Got timeout %v - want %v", client.Timeout, time.Minute)
+	}
+	if transport.MaxIdleConns != DefaultMaxIdleConns || transport.IdleConnTimeout != DefaultIdleConnTimeout || transport.TLSHandshakeTimeout != DefaultTLSHandshakeTimeout {
+		t.Fatalf("Defaults not applied: %+v", transport)
+	}
+	if transport.MaxIdleConnsPerHost != 8 || transport.MaxConnsPerHost != 16 {
+		t.Fatalf("Got %d idle and %d max. connections per host - want 8 and 16", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+	}
+	if transport.Proxy == nil || transport.TLSClientConfig == nil {
+		t.Fatal("Transport has no proxy or TLS config")
+	}
+
+	if _, err = NewClient(Config{TLS: TLSConfig{CAFile: "missing.pem"}}); err == nil {
+		t.Fatal("Created a client with an invalid TLS config")
+	}
+}
@@ -1,5 +1,12 @@
 package xhttp
 
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
 type Credentials struct {
 	ProjectDomain  string
 	ProjectName    string
@@ -7,16 +14,131 @@ type Credentials struct {
 	Username       string
 	Password       string
 	UserDomainName string
+
+	ProjectID       string // Scopes the token to the project with this ID. Takes precedence over ProjectName.
+	ProjectDomainID string // The ID of the domain of ProjectName. Alternative to ProjectDomain.
+	DomainID        string // Scopes the token to the domain with this ID.
+	DomainName      string // Scopes the token to the domain with this name.
+	System          bool   // Scopes the token to the entire deployment (system scope).
+	Unscoped        bool   // Requests an unscoped token.
+
+	// Auth is the authentication method. If nil, the password
+	// method with Username, UserDomainName and Password is used.
+	Auth Authenticator
 }
 
+// Scope returns the scope of the token to request. It returns
+// an error if no scope or more than one scope is configured.
+func (c *Credentials) Scope() (*Scope, error) {
+	var scopes []*Scope
+	if c.Unscoped {
+		scopes = append(scopes, &Scope{Unscoped: true})
+	}
+	if c.System {
+		scopes = append(scopes, &Scope{System: &System{All: true}})
+	}
+	switch {
+	case c.ProjectID != "":
+		scopes = append(scopes, &Scope{Project: &Project{ID: c.ProjectID}})
+	case c.ProjectName != "":
+		if c.ProjectDomain == "" && c.ProjectDomainID == "" {
+			return nil, errors.New("project domain is empty")
+		}
+		domain := &Domain{ID: c.ProjectDomainID}
+		if domain.ID == "" {
+			domain.Name = c.ProjectDomain
+		}
+		scopes = append(scopes, &Scope{Project: &Project{Name: c.ProjectName, Domain: domain}})
+	}
+	switch {
+	case c.DomainID != "":
+		scopes = append(scopes, &Scope{Domain: &Domain{ID: c.DomainID}})
+	case c.DomainName != "":
+		scopes = append(scopes, &Scope{Domain: &Domain{Name: c.DomainName}})
+	}
+
+	switch len(scopes) {
+	case 0:
+		return nil, errors.New("project name is empty")
+	case 1:
+		return scopes[0], nil
+	default:
+		return nil, errors.New("more than one of project, domain, system scope and unscoped is set")
+	}
+}
+
+// Authenticator returns the configured authentication method.
+func (c *Credentials) Authenticator() Authenticator {
+	if c.Auth != nil {
+		return c.Auth
+	}
+	return PasswordAuth{
+		Username:       c.Username,
+		UserDomainName: c.UserDomainName,
+		Password:       c.Password,
+	}
+}
+
+// APIVersionHeader is the header used to request a
+// specific microversion of an OpenStack API.
+const APIVersionHeader = "OpenStack-API-Version"
+
 // Config is a structure containing configuration
 // options for connecting to a Barbican server.
 type Config struct {
-	// Endpoint is the Barbican instance endpoint.
+	// Endpoint is the Barbican instance endpoint. If empty,
+	// the endpoint is discovered from the Keystone service
+	// catalog using ServiceType, ServiceName, Region and
+	// Interface.
 	Endpoint string
 
+	// ServiceType is the catalog service type of Barbican.
+	// Defaults to DefaultServiceType (key-manager).
+	ServiceType string
+
+	// ServiceName optionally restricts the catalog lookup
+	// to services with this name.
+	ServiceName string
+
+	// Region is the catalog region of the Barbican endpoint.
+	// It may be empty if the catalog contains a single
+	// Barbican endpoint.
+	Region string
+
+	// Interface is the catalog endpoint interface: public,
+	// internal or admin. Defaults to DefaultInterface (public).
+	Interface string
+
 	// Credentials used to login to OpenStack to retrieve the APIKey
 	Login Credentials
+
+	// TLS configures the TLS connections to Keystone
+	// and Barbican.
+	TLS TLSConfig
+
+	// Transport configures the HTTP transport, e.g. timeouts,
+	// proxy and connection pool sizes, or replaces it with a
+	// custom http.RoundTripper.
+	Transport TransportConfig
+
+	// HTTPClient, if set, is used for all requests to Keystone
+	// and Barbican. Then TLS and Transport are ignored.
+	HTTPClient *http.Client
+
+	// Retry controls how requests to Keystone and Barbican
+	// are retried on transient errors. By default, requests
+	// are not retried.
+	Retry RetryPolicy
+
+	// TokenRefreshSkew is how long before its expiry the
+	// authentication token is renewed. Defaults to
+	// DefaultTokenRefreshSkew.
+	TokenRefreshSkew time.Duration
+
+	// Microversion pins the key-manager API microversion, e.g.
+	// "1.0". By default, the highest microversion supported by
+	// both the server and the SDK is negotiated.
+	Microversion string
 }
 
 // Auth request structures
@@ -27,25 +149,62 @@ type AuthRequest struct {
 
 type Auth struct {
 	Identity AuthIdentity `json:"identity"`
-	Scope    Scope        `json:"scope"`
+	Scope    *Scope       `json:"scope,omitempty"`
 }
 
 type Scope struct {
-	Project Project `json:"project"`
+	Project *Project `json:"project,omitempty"`
+	Domain  *Domain  `json:"domain,omitempty"`
+	System  *System  `json:"system,omitempty"`
+
+	// Unscoped requests an unscoped token. Without an explicit
+	// scope, Keystone scopes the token to the user's default
+	// project, so an unscoped scope is sent as "unscoped".
+	Unscoped bool `json:"-"`
+}
+
+// unscoped is the JSON encoding of an unscoped Scope.
+const unscoped = `"unscoped"`
+
+func (s Scope) MarshalJSON() ([]byte, error) {
+	if s.Unscoped {
+		return []byte(unscoped), nil
+	}
+	type scope Scope // Avoid infinite recursion
+	return json.Marshal(scope(s))
+}
+
+func (s *Scope) UnmarshalJSON(b []byte) error {
+	if string(b) == unscoped {
+		*s = Scope{Unscoped: true}
+		return nil
+	}
+	type scope Scope // Avoid infinite recursion
+	return json.Unmarshal(b, (*scope)(s))
 }
 
 type Project struct {
-	Domain Name   `json:"domain"`
-	Name   string `json:"name"`
+	ID     string  `json:"id,omitempty"`
+	Domain *Domain `json:"domain,omitempty"`
+	Name   string  `json:"name,omitempty"`
 }
 
-type AuthIdentity struct {
-	Methods  []string `json:"methods"`
-	Password Password `json:"password"`
+// Domain identifies a domain either by ID or by name.
+type Domain struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type System struct {
+	All bool `json:"all"`
 }
 
-type Name struct {
-	Name string `json:"name"`
+type AuthIdentity struct {
+	Methods               []string               `json:"methods"`
+	Password              *Password              `json:"password,omitempty"`
+	Token                 *TokenIdentity         `json:"token,omitempty"`
+	ApplicationCredential *ApplicationCredential `json:"application_credential,omitempty"`
+	TOTP                  *TOTP                  `json:"totp,omitempty"`
 }
 
 type Password struct {
@@ -53,9 +212,26 @@ type Password struct {
 }
 
 type User struct {
-	Domain   Name   `json:"domain"`
-	Name     string `json:"name"`
-	Password string `json:"password"`
+	ID       string  `json:"id,omitempty"`
+	Domain   *Domain `json:"domain,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Password string  `json:"password,omitempty"`
+	Passcode string  `json:"passcode,omitempty"`
+}
+
+type TokenIdentity struct {
+	ID string `json:"id"`
+}
+
+type ApplicationCredential struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Secret string `json:"secret"`
+	User   *User  `json:"user,omitempty"`
+}
+
+type TOTP struct {
+	User User `json:"user"`
 }
 
 // Auth response structures
@@ -65,5 +241,6 @@ type AuthResponse struct {
 }
 
 type Token struct {
-	ExpiresAt string `json:"expires_at"`
+	ExpiresAt string  `json:"expires_at"`
+	Catalog   Catalog `json:"catalog"`
 }
@@ -20,8 +20,9 @@ import (
 // authToken is a Barbican authentication token.
 // It can be used to authenticate API requests.
 type authToken struct {
-	Key    string
-	Expiry time.Time
+	Key       string
+	Expiry    time.Time
+	RefreshAt time.Time
 }
 
 // client is a Barbican REST API client
@@ -31,57 +32,60 @@ type Client struct {
 	http.Client
 	config Config
 
-	lock  sync.Mutex
-	token authToken
-}
+	lock    sync.Mutex
+	token   authToken
+	catalog Catalog
+	refresh *refresh
+	closed  bool
 
-// Add auth header to request
-func (c *Client) setAuthHeader(ctx context.Context, config Config, h *http.Header) error {
-	if c.token.Expiry.Unix() < time.Now().Unix() {
-		err := c.Authenticate(ctx, config)
-		if err != nil {
-			return err
-		}
-	}
-	h.Add("X-Auth-Token", string(c.token.Key))
-	return nil
+	microversion string
 }
 
 // Authenticate tries to obtain a new authentication token
 // from the given Barbican endpoint via the given credentials.
 //
 // Authenticate should be called to obtain the first authentication
-// token. This token can then be renewed via RenewApiToken.
+// token. The client then renews the token with the same config
+// ahead of its expiry - see Config.TokenRefreshSkew.
 func (c *Client) Authenticate(ctx context.Context, config Config) error {
-	r := AuthRequest{}
-	r.Auth.Identity.Methods = []string{"password"}
-	r.Auth.Identity.Password.User.Domain.Name = config.Login.UserDomainName
-	r.Auth.Identity.Password.User.Name = config.Login.Username
-	r.Auth.Identity.Password.User.Password = config.Login.Password
-	r.Auth.Scope.Project.Domain.Name = config.Login.ProjectDomain
-	r.Auth.Scope.Project.Name = config.Login.ProjectName
-
-	body, err := json.Marshal(&r)
+	identity, err := config.Login.Authenticator().Identity()
 	if err != nil {
 		return err
 	}
+	r := AuthRequest{}
+	r.Auth.Identity = identity
+	// Application credentials are bound to a project.
+	// Keystone rejects requests scoping them explicitly.
+	if identity.ApplicationCredential == nil {
+		if r.Auth.Scope, err = config.Login.Scope(); err != nil {
+			return err
+		}
+	}
 
-	url := fmt.Sprintf("%s/v3/auth/tokens", config.Login.AuthUrl)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	body, err := json.Marshal(&r)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.Do(req)
+	url := tokensURL(config.Login.AuthUrl)
+	resp, err := config.Retry.do(ctx, &c.Client, true, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		err := parseErrorResponse(resp)
-		return fmt.Errorf("%s: %v", resp.Status, err)
+		if err := parseErrorResponse(resp); err != nil {
+			return err
+		}
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
 	}
 
 	const MaxSize = 1 * mem.MiB // An auth. token response should not exceed 1 MiB
@@ -103,18 +107,104 @@ func (c *Client) Authenticate(ctx context.Context, config Config) error {
 	}
 
 	c.lock.Lock()
+	if c.closed {
+		// The client got closed while authenticating.
+		// Don't keep the new token around.
+		c.lock.Unlock()
+		c.revoke(ctx, config.Login.AuthUrl, token)
+		return xerror.ErrClosed
+	}
+	c.config = config
 	c.token = authToken{
-		Key:    token,
-		Expiry: expiry,
+		Key:       token,
+		Expiry:    expiry,
+		RefreshAt: refreshAt(expiry, config.TokenRefreshSkew),
 	}
+	c.catalog = response.Token.Catalog
 	c.lock.Unlock()
 	return nil
 }
 
-// parseErrorResponse returns an error containing
-// the response status code and response body
-// as error message if the response is an error
-// response - i.e. status code >= 400.
+// Close revokes the current authentication token and releases
+// idle HTTP connections. Once closed, all requests fail with
+// xerror.ErrClosed. Closing a closed client is a no-op.
+func (c *Client) Close(ctx context.Context) error {
+	c.lock.Lock()
+	if c.closed {
+		c.lock.Unlock()
+		return nil
+	}
+	c.closed = true
+	token, authURL := c.token, c.config.Login.AuthUrl
+	c.token, c.catalog = authToken{}, nil
+	c.lock.Unlock()
+
+	defer c.CloseIdleConnections()
+	if token.Key == "" || !time.Now().Before(token.Expiry) {
+		return nil
+	}
+	return c.revoke(ctx, authURL, token.Key)
+}
+
+// revoke invalidates the given token at Keystone.
+func (c *Client) revoke(ctx context.Context, authURL, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, tokensURL(authURL), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	req.Header.Set("X-Subject-Token", token)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Keystone responds with 404 if the token
+	// is not valid anymore - e.g. already revoked.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return parseErrorResponse(resp)
+}
+
+// DiscoverEndpoint returns the Barbican endpoint from the service
+// catalog of the current token, as selected by config. Unscoped
+// tokens do not come with a service catalog.
+func (c *Client) DiscoverEndpoint(config Config) (string, error) {
+	serviceType, iface := config.ServiceType, config.Interface
+	if serviceType == "" {
+		serviceType = DefaultServiceType
+	}
+	if iface == "" {
+		iface = DefaultInterface
+	}
+
+	c.lock.Lock()
+	catalog := c.catalog
+	c.lock.Unlock()
+	if len(catalog) == 0 {
+		return "", errors.New("token does not contain a service catalog")
+	}
+	return catalog.Endpoint(serviceType, config.ServiceName, config.Region, iface)
+}
+
+// tokensURL returns the Keystone token endpoint of the given
+// auth URL, which may or may not contain the /v3 version suffix.
+func tokensURL(authURL string) string {
+	authURL = strings.TrimSuffix(strings.TrimSpace(authURL), "/")
+	authURL = strings.TrimSuffix(authURL, "/v3")
+	return authURL + "/v3/auth/tokens"
+}
+
+// parseErrorResponse returns an xerror.Error describing the
+// error response - i.e. status code >= 400 - of a request.
+//
+// The error contains the title and message of the fault body
+// sent by Barbican or Keystone, the request method and URL and
+// the request ID assigned by the server. If the body is not a
+// JSON fault, its text is used as message.
 //
 // If the response status code is < 400, e.g. 200 OK,
 // parseErrorResponse returns nil and does not attempt
@@ -126,8 +216,16 @@ func parseErrorResponse(resp *http.Response) error {
 	if resp.StatusCode < 400 {
 		return nil
 	}
+	var method, url string
+	if resp.Request != nil {
+		method, url = resp.Request.Method, resp.Request.URL.String()
+	}
+	requestID := resp.Header.Get("X-Openstack-Request-Id")
+	if requestID == "" {
+		requestID = resp.Header.Get("X-Compute-Request-Id")
+	}
 	if resp.Body == nil {
-		return xerror.NewError(resp.StatusCode, resp.Status)
+		return xerror.NewError(resp.StatusCode, resp.Status).WithRequest(method, url, requestID)
 	}
 	defer resp.Body.Close()
 
@@ -136,23 +234,38 @@ func parseErrorResponse(resp *http.Response) error {
 	if size < 0 || size > MaxSize {
 		size = MaxSize
 	}
+	body, err := io.ReadAll(mem.LimitReader(resp.Body, size))
+	if err != nil {
+		return xerror.NewError(resp.StatusCode, err.Error()).WithRequest(method, url, requestID)
+	}
 
 	if contentType := strings.TrimSpace(resp.Header.Get("Content-Type")); strings.HasPrefix(contentType, "application/json") {
-		type Response struct {
-			Message string `json:"message"`
+		// Barbican sends faults as {"code", "title", "description"}
+		// while Keystone wraps them: {"error": {"code", "title", "message"}}.
+		type Fault struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Message     string `json:"message"`
 		}
-		var response Response
-		if err := json.NewDecoder(mem.LimitReader(resp.Body, size)).Decode(&response); err != nil {
-			return err
+		var response struct {
+			Fault
+			Error *Fault `json:"error"`
+		}
+		if err := json.Unmarshal(body, &response); err == nil {
+			fault := response.Fault
+			if response.Error != nil {
+				fault = *response.Error
+			}
+			message := fault.Description
+			if message == "" {
+				message = fault.Message
+			}
+			if fault.Title != "" || message != "" {
+				return xerror.NewFault(resp.StatusCode, fault.Title, message).WithRequest(method, url, requestID)
+			}
 		}
-		return xerror.NewError(resp.StatusCode, response.Message)
-	}
-
-	var sb strings.Builder
-	if _, err := io.Copy(&sb, mem.LimitReader(resp.Body, size)); err != nil {
-		return err
 	}
-	return xerror.NewError(resp.StatusCode, sb.String())
+	return xerror.NewError(resp.StatusCode, strings.TrimSpace(string(body))).WithRequest(method, url, requestID)
 }
 
 func (c *Client) request(ctx context.Context, method string, address string, payload []byte, params url.Values) ([]byte, error) {
@@ -165,32 +278,67 @@ func (c *Client) request(ctx context.Context, method string, address string, pay
 		url.RawQuery = params.Encode()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url.String(), bytes.NewReader(payload))
+	token, err := c.authToken(ctx, "")
 	if err != nil {
 		return nil, err
 	}
-	err = c.setAuthHeader(ctx, c.config, &req.Header)
+	resp, err := c.send(ctx, method, url.String(), payload, token)
 	if err != nil {
 		return nil, err
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.Do(req)
-	if err != nil {
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The token may have been revoked or expired early.
+		// Re-authenticate once and retry the request.
+		resp.Body.Close()
+		if token, err = c.authToken(ctx, token); err != nil {
+			return nil, err
+		}
+		if resp, err = c.send(ctx, method, url.String(), payload, token); err != nil {
+			return nil, err
+		}
+	}
+	if err := parseErrorResponse(resp); err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, xerror.NewError(resp.StatusCode, err.Error())
+		return nil, xerror.NewError(resp.StatusCode, err.Error()).WithRequest(method, url.String(), resp.Header.Get("X-Openstack-Request-Id"))
 	}
+	return body, nil
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode >= 400 {
-		return nil, xerror.NewError(resp.StatusCode, string(body))
-	}
+// send sends a request authenticated with the given token
+// and retries it on transient errors as configured.
+func (c *Client) send(ctx context.Context, method, url string, payload []byte, token string) (*http.Response, error) {
+	c.lock.Lock()
+	policy, microversion := c.config.Retry, c.microversion
+	c.lock.Unlock()
 
-	return body, nil
+	retryable := isIdempotent(method) || policy.RetryNonIdempotent
+	return policy.do(ctx, &c.Client, retryable, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Auth-Token", token)
+		req.Header.Set("Content-Type", "application/json")
+		if microversion != "" {
+			req.Header.Set(APIVersionHeader, DefaultServiceType+" "+microversion)
+		}
+		return req, nil
+	})
+}
+
+// SetMicroversion sets the key-manager API microversion, e.g.
+// "1.1", requested by all subsequent requests. If version is
+// empty, no microversion is requested and the server uses its
+// minimum microversion.
+func (c *Client) SetMicroversion(version string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.microversion = version
 }
 
 func (c *Client) HttpGet(ctx context.Context, url string, params url.Values) ([]byte, error) {
@@ -205,6 +353,10 @@ func (c *Client) HttpPut(ctx context.Context, url string, payload []byte, params
 	return c.request(ctx, http.MethodPut, url, payload, params)
 }
 
+func (c *Client) HttpPatch(ctx context.Context, url string, payload []byte, params url.Values) ([]byte, error) {
+	return c.request(ctx, http.MethodPatch, url, payload, params)
+}
+
 func (c *Client) HttpDelete(ctx context.Context, url string, payload []byte, params url.Values) ([]byte, error) {
 	return c.request(ctx, http.MethodDelete, url, payload, params)
 }
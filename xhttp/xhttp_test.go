@@ -0,0 +1,167 @@
+package xhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer is a minimal Keystone and Barbican server.
+// It issues tokens at /v3/auth/tokens and serves all other
+// paths to requests carrying a valid token.
+type testServer struct {
+	*httptest.Server
+
+	lock      sync.Mutex
+	auths     int           // Number of token requests
+	authBody  []byte        // Body of the last token request
+	authDelay time.Duration // Delay of token responses
+	authFail  bool          // Fail token requests with 500
+	tokens    map[string]bool
+	revoked   []string
+
+	// resource, if set, handles authenticated requests.
+	// Otherwise, they are answered with 200 OK.
+	resource http.HandlerFunc
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	s := &testServer{tokens: map[string]bool{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v3/auth/tokens" {
+		switch r.Method {
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			s.lock.Lock()
+			s.authBody = body
+			s.lock.Unlock()
+			s.issueToken(w)
+		case http.MethodDelete:
+			s.revokeToken(w, r.Header.Get("X-Subject-Token"))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	s.lock.Lock()
+	valid, resource := s.tokens[r.Header.Get("X-Auth-Token")], s.resource
+	s.lock.Unlock()
+	if !valid {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":401,"title":"Unauthorized","message":"The request you have made requires authentication."}}`))
+		return
+	}
+	if resource != nil {
+		resource(w, r)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+func (s *testServer) issueToken(w http.ResponseWriter) {
+	s.lock.Lock()
+	s.auths++
+	token, delay, fail := fmt.Sprintf("token-%d", s.auths), s.authDelay, s.authFail
+	if !fail {
+		s.tokens[token] = true
+	}
+	s.lock.Unlock()
+
+	time.Sleep(delay)
+	if fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Subject-Token", token)
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, `{"token":{"expires_at":%q,"catalog":[]}}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+}
+
+func (s *testServer) revokeToken(w http.ResponseWriter, token string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.tokens[token] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	delete(s.tokens, token)
+	s.revoked = append(s.revoked, token)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// invalidate revokes the given token without
+// the client noticing.
+func (s *testServer) invalidate(token string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.tokens, token)
+}
+
+func (s *testServer) authCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.auths
+}
+
+// config returns a Config for authenticating at s.
+func (s *testServer) config() Config {
+	return Config{
+		Endpoint: s.URL + "/v1",
+		Login: Credentials{
+			AuthUrl:        s.URL + "/v3",
+			Username:       "user",
+			Password:       "password",
+			UserDomainName: "Default",
+			ProjectName:    "project",
+			ProjectDomain:  "Default",
+		},
+	}
+}
+
+// newTestClient returns a client authenticated at s.
+func newTestClient(t *testing.T, s *testServer, config Config) *Client {
+	t.Helper()
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err = client.Authenticate(context.Background(), config); err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	return client
+}
+
+func TestTokensURL(t *testing.T) {
+	for i, test := range tokensURLTests {
+		if url := tokensURL(test.AuthURL); url != test.URL {
+			t.Fatalf("Test %d: got '%s' - want '%s'", i, url, test.URL)
+		}
+	}
+}
+
+var tokensURLTests = []struct {
+	AuthURL string
+	URL     string
+}{
+	{AuthURL: "https://keystone:5000", URL: "https://keystone:5000/v3/auth/tokens"},
+	{AuthURL: "https://keystone:5000/", URL: "https://keystone:5000/v3/auth/tokens"},
+	{AuthURL: "https://keystone:5000/v3", URL: "https://keystone:5000/v3/auth/tokens"},
+	{AuthURL: " https://keystone:5000/v3/ ", URL: "https://keystone:5000/v3/auth/tokens"},
+	{AuthURL: "https://cloud/identity/v3", URL: "https://cloud/identity/v3/auth/tokens"},
+}